@@ -0,0 +1,327 @@
+// Package graph provides graph algorithms over the resources and relationships of a resources.ResourceCollection, such
+// as the dependency order of the resources, cycle detection, reachability and strongly connected components.
+//
+// Relationships are directed from the source to the target. Relationships with nil endpoints or endpoints that are
+// not in the collection are ignored, and resources are identified by ID.
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/diagram-code-generator/resources/pkg/resources"
+)
+
+var ErrCycle = errors.New("cycle detected")
+
+// CycleError is returned when the relationships have a cycle. Path starts and ends with the same resource.
+type CycleError struct {
+	Path []resources.Resource
+}
+
+func (e *CycleError) Error() string {
+	values := make([]string, 0, len(e.Path))
+	for _, res := range e.Path {
+		values = append(values, res.Value())
+	}
+
+	return fmt.Sprintf("%v: %s", ErrCycle, strings.Join(values, " -> "))
+}
+
+func (e *CycleError) Unwrap() error { return ErrCycle }
+
+// graph is the adjacency list of a resource collection, where the resources are referenced by their position in the
+// collection.
+type graph struct {
+	nodes []resources.Resource
+	adj   [][]int
+}
+
+func newGraph(rc *resources.ResourceCollection) *graph {
+	g := &graph{}
+	positions := map[string]int{}
+
+	for _, res := range rc.Resources {
+		if res == nil {
+			continue
+		}
+
+		if _, ok := positions[res.ID()]; !ok {
+			positions[res.ID()] = len(g.nodes)
+			g.nodes = append(g.nodes, res)
+		}
+	}
+
+	g.adj = make([][]int, len(g.nodes))
+
+	for _, rel := range rc.Relationships {
+		if rel.Source == nil || rel.Target == nil {
+			continue
+		}
+
+		from, okFrom := positions[rel.Source.ID()]
+		to, okTo := positions[rel.Target.ID()]
+
+		if okFrom && okTo {
+			g.adj[from] = append(g.adj[from], to)
+		}
+	}
+
+	return g
+}
+
+// byID returns the node positions sorted by resource ID.
+func (g *graph) byID() []int {
+	order := make([]int, len(g.nodes))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(i, j int) bool { return g.nodes[order[i]].ID() < g.nodes[order[j]].ID() })
+
+	return order
+}
+
+func (g *graph) resources(positions []int) []resources.Resource {
+	result := make([]resources.Resource, 0, len(positions))
+	for _, i := range positions {
+		result = append(result, g.nodes[i])
+	}
+
+	return result
+}
+
+// TopologicalSort returns the resources in dependency order: every resource comes before the targets of its
+// relationships. When several resources are ready at the same time, the one with the smallest ID comes first, so the
+// order is deterministic. A *CycleError is returned when the relationships have a cycle.
+func TopologicalSort(rc *resources.ResourceCollection) ([]resources.Resource, error) {
+	g := newGraph(rc)
+
+	inDegree := make([]int, len(g.nodes))
+
+	for _, targets := range g.adj {
+		for _, to := range targets {
+			inDegree[to]++
+		}
+	}
+
+	// ready is kept sorted by ID, so the next resource is always the first one.
+	var ready []int
+
+	push := func(i int) {
+		pos := sort.Search(len(ready), func(j int) bool { return g.nodes[ready[j]].ID() > g.nodes[i].ID() })
+		ready = append(ready, 0)
+		copy(ready[pos+1:], ready[pos:])
+		ready[pos] = i
+	}
+
+	for _, i := range g.byID() {
+		if inDegree[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	order := make([]int, 0, len(g.nodes))
+
+	for len(ready) > 0 {
+		i := ready[0]
+		ready = ready[1:]
+		order = append(order, i)
+
+		for _, to := range g.adj[i] {
+			inDegree[to]--
+			if inDegree[to] == 0 {
+				push(to)
+			}
+		}
+	}
+
+	if len(order) < len(g.nodes) {
+		return nil, &CycleError{Path: g.resources(g.findCycle())}
+	}
+
+	return g.resources(order), nil
+}
+
+// FindCycle returns the path of a cycle in the relationships, starting and ending with the same resource, or nil when
+// there is none. The search starts from the resources with the smallest IDs, so the same cycle is always reported.
+func FindCycle(rc *resources.ResourceCollection) []resources.Resource {
+	g := newGraph(rc)
+
+	cycle := g.findCycle()
+	if cycle == nil {
+		return nil
+	}
+
+	return g.resources(cycle)
+}
+
+func (g *graph) findCycle() []int {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make([]int, len(g.nodes))
+
+	var (
+		stack []int
+		visit func(i int) []int
+	)
+
+	visit = func(i int) []int {
+		state[i] = visiting
+		stack = append(stack, i)
+
+		for _, to := range g.adj[i] {
+			switch state[to] {
+			case visiting:
+				for start := len(stack) - 1; start >= 0; start-- {
+					if stack[start] == to {
+						return append(append([]int{}, stack[start:]...), to)
+					}
+				}
+			case unvisited:
+				if cycle := visit(to); cycle != nil {
+					return cycle
+				}
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[i] = visited
+
+		return nil
+	}
+
+	for _, i := range g.byID() {
+		if state[i] == unvisited {
+			if cycle := visit(i); cycle != nil {
+				return cycle
+			}
+		}
+	}
+
+	return nil
+}
+
+// Reachable returns the resources that can be reached from the given resource by following the relationships, in the
+// collection order. The resource itself is only included when it is part of a cycle.
+func Reachable(rc *resources.ResourceCollection, from resources.Resource) []resources.Resource {
+	g := newGraph(rc)
+
+	start := -1
+
+	for i, res := range g.nodes {
+		if res.ID() == from.ID() {
+			start = i
+			break
+		}
+	}
+
+	if start < 0 {
+		return nil
+	}
+
+	reached := make([]bool, len(g.nodes))
+	queue := append([]int{}, g.adj[start]...)
+
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+
+		if reached[i] {
+			continue
+		}
+
+		reached[i] = true
+		queue = append(queue, g.adj[i]...)
+	}
+
+	var result []resources.Resource
+
+	for i, ok := range reached {
+		if ok {
+			result = append(result, g.nodes[i])
+		}
+	}
+
+	return result
+}
+
+// StronglyConnectedComponents returns the groups of resources that can all reach each other, using Tarjan's
+// algorithm. Resources without cycles are components of their own. The resources of each component, and the
+// components themselves by their first resource, are in the collection order.
+func StronglyConnectedComponents(rc *resources.ResourceCollection) [][]resources.Resource {
+	g := newGraph(rc)
+
+	var (
+		index      int
+		stack      []int
+		components [][]int
+		connect    func(i int)
+	)
+
+	indexes := make([]int, len(g.nodes))
+	lowLinks := make([]int, len(g.nodes))
+	onStack := make([]bool, len(g.nodes))
+
+	for i := range indexes {
+		indexes[i] = -1
+	}
+
+	connect = func(i int) {
+		indexes[i], lowLinks[i] = index, index
+		index++
+
+		stack = append(stack, i)
+		onStack[i] = true
+
+		for _, to := range g.adj[i] {
+			if indexes[to] < 0 {
+				connect(to)
+				lowLinks[i] = min(lowLinks[i], lowLinks[to])
+			} else if onStack[to] {
+				lowLinks[i] = min(lowLinks[i], indexes[to])
+			}
+		}
+
+		if lowLinks[i] != indexes[i] {
+			return
+		}
+
+		var component []int
+
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			component = append(component, top)
+
+			if top == i {
+				break
+			}
+		}
+
+		sort.Ints(component)
+		components = append(components, component)
+	}
+
+	for i := range g.nodes {
+		if indexes[i] < 0 {
+			connect(i)
+		}
+	}
+
+	sort.Slice(components, func(i, j int) bool { return components[i][0] < components[j][0] })
+
+	result := make([][]resources.Resource, 0, len(components))
+	for _, component := range components {
+		result = append(result, g.resources(component))
+	}
+
+	return result
+}
@@ -0,0 +1,162 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/diagram-code-generator/resources/pkg/resources"
+)
+
+func TestTopologicalSort(t *testing.T) {
+	apiGateway := resources.NewGenericResource("1", "MyAPI", "apigateway")
+	lambda := resources.NewGenericResource("2", "MyLambda", "lambda")
+	queue := resources.NewGenericResource("3", "my-queue", "sqs")
+	database := resources.NewGenericResource("4", "MyTable", "dynamodb")
+	bucket := resources.NewGenericResource("5", "my-bucket", "s3")
+
+	tests := []struct {
+		name      string
+		rc        *resources.ResourceCollection
+		want      []resources.Resource
+		wantCycle []resources.Resource
+	}{
+		{
+			name: "dependency order with ties broken by ID",
+			rc: &resources.ResourceCollection{
+				Resources: []resources.Resource{bucket, database, queue, lambda, apiGateway},
+				Relationships: []resources.Relationship{
+					{Source: apiGateway, Target: lambda},
+					{Source: lambda, Target: database},
+					{Source: queue, Target: lambda},
+					{Source: lambda, Target: resources.NewGenericResource("9", "Unknown", "sqs")},
+					{Source: nil, Target: lambda},
+				},
+			},
+			want: []resources.Resource{apiGateway, queue, lambda, database, bucket},
+		},
+		{
+			name: "cycle",
+			rc: &resources.ResourceCollection{
+				Resources: []resources.Resource{apiGateway, lambda, queue, database},
+				Relationships: []resources.Relationship{
+					{Source: apiGateway, Target: lambda},
+					{Source: lambda, Target: queue},
+					{Source: queue, Target: database},
+					{Source: database, Target: lambda},
+				},
+			},
+			wantCycle: []resources.Resource{lambda, queue, database, lambda},
+		},
+		{
+			name: "self-loop",
+			rc: &resources.ResourceCollection{
+				Resources:     []resources.Resource{lambda},
+				Relationships: []resources.Relationship{{Source: lambda, Target: lambda}},
+			},
+			wantCycle: []resources.Resource{lambda, lambda},
+		},
+		{
+			name: "empty collection",
+			rc:   resources.NewResourceCollection(),
+			want: []resources.Resource{},
+		},
+	}
+
+	for i := range tests {
+		tc := tests[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := TopologicalSort(tc.rc)
+
+			if tc.wantCycle != nil {
+				var cycleErr *CycleError
+
+				require.ErrorIs(t, err, ErrCycle)
+				require.ErrorAs(t, err, &cycleErr)
+				require.Equal(t, tc.wantCycle, cycleErr.Path)
+				require.Equal(t, tc.wantCycle, FindCycle(tc.rc))
+				require.Nil(t, got)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+			require.Nil(t, FindCycle(tc.rc))
+		})
+	}
+}
+
+func TestCycleError_Error(t *testing.T) {
+	lambda := resources.NewGenericResource("1", "MyLambda", "lambda")
+	queue := resources.NewGenericResource("2", "my-queue", "sqs")
+
+	err := &CycleError{Path: []resources.Resource{lambda, queue, lambda}}
+
+	require.EqualError(t, err, "cycle detected: MyLambda -> my-queue -> MyLambda")
+}
+
+func TestReachable(t *testing.T) {
+	lambda := resources.NewGenericResource("1", "MyLambda", "lambda")
+	queue := resources.NewGenericResource("2", "my-queue", "sqs")
+	stream := resources.NewGenericResource("3", "MyStream", "kinesis")
+	database := resources.NewGenericResource("4", "MyTable", "dynamodb")
+
+	rc := &resources.ResourceCollection{
+		Resources: []resources.Resource{lambda, queue, stream, database},
+		Relationships: []resources.Relationship{
+			{Source: lambda, Target: stream},
+			{Source: stream, Target: queue},
+			{Source: queue, Target: stream},
+		},
+	}
+
+	tests := []struct {
+		name string
+		from resources.Resource
+		want []resources.Resource
+	}{
+		{name: "transitive", from: lambda, want: []resources.Resource{queue, stream}},
+		{name: "in a cycle", from: queue, want: []resources.Resource{queue, stream}},
+		{name: "no relationships", from: database, want: nil},
+		{name: "not in the collection", from: resources.NewGenericResource("9", "Unknown", "sqs"), want: nil},
+	}
+
+	for i := range tests {
+		tc := tests[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, Reachable(rc, tc.from))
+		})
+	}
+}
+
+func TestStronglyConnectedComponents(t *testing.T) {
+	lambda := resources.NewGenericResource("1", "MyLambda", "lambda")
+	queue := resources.NewGenericResource("2", "my-queue", "sqs")
+	stream := resources.NewGenericResource("3", "MyStream", "kinesis")
+	database := resources.NewGenericResource("4", "MyTable", "dynamodb")
+	bucket := resources.NewGenericResource("5", "my-bucket", "s3")
+
+	rc := &resources.ResourceCollection{
+		Resources: []resources.Resource{lambda, queue, stream, database, bucket},
+		Relationships: []resources.Relationship{
+			{Source: lambda, Target: queue},
+			{Source: queue, Target: stream},
+			{Source: stream, Target: lambda},
+			{Source: stream, Target: database},
+			{Source: database, Target: bucket},
+			{Source: bucket, Target: database},
+		},
+	}
+
+	got := StronglyConnectedComponents(rc)
+
+	require.Equal(t, [][]resources.Resource{
+		{lambda, queue, stream},
+		{database, bucket},
+	}, got)
+
+	require.Empty(t, StronglyConnectedComponents(resources.NewResourceCollection()))
+}
@@ -0,0 +1,8 @@
+//go:build cgo
+
+package layout
+
+// New returns the Graphviz layouter, as cgo is enabled.
+func New(config *Config) Layouter {
+	return NewGraphviz(config)
+}
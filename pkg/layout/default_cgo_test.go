@@ -0,0 +1,13 @@
+//go:build cgo
+
+package layout
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	require.IsType(t, &Graphviz{}, New(nil))
+}
@@ -0,0 +1,8 @@
+//go:build !cgo
+
+package layout
+
+// New returns the layered layouter, as Graphviz needs cgo.
+func New(config *Config) Layouter {
+	return NewLayered(config)
+}
@@ -0,0 +1,81 @@
+//go:build cgo
+
+package layout
+
+import (
+	"strings"
+
+	svg "github.com/diagram-code-generator/resources/pkg/parser/graphviz/svg"
+	"github.com/diagram-code-generator/resources/pkg/resources"
+)
+
+// Graphviz lays out the resources with the Graphviz library embedded by go-graphviz, which needs cgo.
+type Graphviz struct {
+	config *Config
+}
+
+func NewGraphviz(config *Config) *Graphviz {
+	if config == nil {
+		config = &Config{}
+	}
+
+	return &Graphviz{config: config}
+}
+
+// Layout lays out the resources as the nodes of the Graphviz SVG output, with the relationships routed through the
+// points of its edges that are on the curve, moved from the SVG coordinates to the ones of the Layout.
+func (g *Graphviz) Layout(resc *resources.ResourceCollection) (*Layout, error) {
+	svgData, err := svg.NewSVGDiagramWithConfig(&svg.Config{
+		Engine:    g.config.Engine,
+		Direction: g.config.Direction,
+		NodeSep:   g.config.NodeSep,
+		RankSep:   g.config.RankSep,
+	}).Build(resc)
+	if err != nil {
+		return nil, err
+	}
+
+	layout := &Layout{Nodes: map[string]Rect{}, Edges: make([]Edge, len(resc.Relationships))}
+
+	// Edges between the same resources share a title, so they are matched to the relationships in order.
+	edgeNodes := map[string][]svg.Node{}
+
+	for _, node := range svgData.G.Nodes {
+		switch node.Class {
+		case svg.ClassNode:
+			if b, ok := node.Bounds(); ok {
+				id := strings.Split(node.Title, svg.ResourceInfoSeparator)[0]
+				layout.Nodes[id] = Rect{X: b.X, Y: b.Y, Width: b.Width, Height: b.Height}
+			}
+		case svg.ClassEdge:
+			edgeNodes[node.Title] = append(edgeNodes[node.Title], node)
+		}
+	}
+
+	for i, rel := range resc.Relationships {
+		if rel.Source == nil || rel.Target == nil {
+			continue
+		}
+
+		title := svg.EdgeTitle(rel)
+
+		nodes := edgeNodes[title]
+		if len(nodes) == 0 {
+			continue
+		}
+
+		edgeNodes[title] = nodes[1:]
+
+		// The path is a B-spline: the points between the ones on the curve, every third one, only bend it.
+		controlPoints := nodes[0].ControlPoints()
+
+		for j := 0; j < len(controlPoints); j += 3 {
+			layout.Edges[i].Points = append(layout.Edges[i].Points, Point{X: controlPoints[j].X, Y: controlPoints[j].Y})
+		}
+	}
+
+	// The SVG coordinates have the origin at the bottom left, so Y is negative.
+	layout.normalize()
+
+	return layout, nil
+}
@@ -0,0 +1,62 @@
+//go:build cgo
+
+package layout
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/diagram-code-generator/resources/pkg/parser/graphviz/dot"
+	"github.com/diagram-code-generator/resources/pkg/resources"
+)
+
+func TestGraphviz_Layout(t *testing.T) {
+	lambda1 := resources.NewGenericResource("1", "lambda1", "lambda")
+	lambda2 := resources.NewGenericResource("2", "lambda2", "lambda")
+	vpc := resources.NewGenericResource("0", "vpc", "vpc")
+
+	got, err := NewGraphviz(nil).Layout(&resources.ResourceCollection{
+		Resources:     []resources.Resource{vpc, lambda1, lambda2},
+		Relationships: []resources.Relationship{{Source: lambda1, Target: lambda2}, {Source: vpc, Target: lambda2}},
+		Parents:       map[string]string{"1": "0"},
+	})
+
+	// The width is computed from the corners of the node polygon, and the SVG coordinates are moved so that the top
+	// of the first node is at 0.
+	left, right, top := 65.9399, 106.0601, -116.0
+
+	require.NoError(t, err)
+	require.Equal(t, &Layout{
+		Nodes: map[string]Rect{
+			"1": {X: 0, Y: 0, Width: right - left, Height: 40},
+			"2": {X: 0, Y: -40 - top, Width: right - left, Height: 40},
+		},
+		Edges: []Edge{
+			{Points: []Point{{X: 86 - left, Y: -75.6334 - top}, {X: 86 - left, Y: -50.183 - top}}},
+			{},
+		},
+	}, got)
+}
+
+func TestGraphviz_Layout_NilEndpoint(t *testing.T) {
+	lambda := resources.NewGenericResource("1", "lambda1", "lambda")
+
+	got, err := NewGraphviz(nil).Layout(&resources.ResourceCollection{
+		Resources:     []resources.Resource{lambda},
+		Relationships: []resources.Relationship{{Source: lambda}, {Target: lambda}},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, []Edge{{}, {}}, got.Edges)
+}
+
+func TestGraphviz_Layout_UnsupportedEngine(t *testing.T) {
+	lambda := resources.NewGenericResource("1", "lambda1", "lambda")
+
+	got, err := NewGraphviz(&Config{Engine: "unknown"}).Layout(
+		&resources.ResourceCollection{Resources: []resources.Resource{lambda}})
+
+	require.ErrorIs(t, err, dot.ErrUnsupportedEngine)
+	require.Nil(t, got)
+}
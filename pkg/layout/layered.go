@@ -0,0 +1,769 @@
+package layout
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/diagram-code-generator/resources/pkg/parser/graphviz/dot"
+	"github.com/diagram-code-generator/resources/pkg/resources"
+)
+
+const (
+	// orderingSweeps is the number of sweeps of the crossing reduction, alternating down and up the ranks.
+	orderingSweeps = 8
+	// positioningPasses is the number of passes moving the nodes towards their neighbors.
+	positioningPasses = 8
+	// clusterPadding is the space kept around the nodes of a container, as the draw.io containers take 20 points
+	// around their children.
+	clusterPadding = 20
+)
+
+// Layered is a pure Go layered layout, in the style of Sugiyama and of the Graphviz dot engine. It ranks the
+// resources along the relationships, orders the resources of each rank to reduce the edge crossings and then moves
+// them towards their neighbors. The children of a container are kept next to each other in each rank.
+type Layered struct {
+	config *Config
+}
+
+func NewLayered(config *Config) *Layered {
+	if config == nil {
+		config = &Config{}
+	}
+
+	return &Layered{config: config}
+}
+
+// layeredNode is a resource, or a dummy node of a relationship spanning several ranks.
+type layeredNode struct {
+	// id is the ID of the resource, empty for dummy nodes.
+	id string
+	// groups are the containers of the resource, from the outermost one.
+	groups []string
+	rank   int
+	// up and down are the nodes linked to the node in the previous and next ranks.
+	up, down []int
+	x        float64
+}
+
+func (n *layeredNode) width() float64 {
+	if n.id == "" {
+		return 0
+	}
+
+	return NodeSize
+}
+
+// layeredEdge is a relationship between two nodes, reversed when it closes a cycle.
+type layeredEdge struct {
+	relationship int
+	from, to     int
+	reversed     bool
+	// chain holds the nodes of the edge from the upper rank to the lower one, with its dummy nodes.
+	chain []int
+}
+
+// Layout ranks the resources, orders the ranks and positions the nodes, then turns the ranks to the configured
+// direction.
+func (l *Layered) Layout(resc *resources.ResourceCollection) (*Layout, error) {
+	nodes, edges := layeredGraph(resc)
+
+	breakCycles(nodes, edges)
+	assignRanks(nodes, edges)
+
+	nodes = insertDummyNodes(nodes, edges)
+	ranks := orderRanks(nodes)
+
+	l.positionNodes(nodes, ranks)
+
+	layout := l.result(resc, nodes, edges, len(ranks))
+	layout.normalize()
+
+	return layout, nil
+}
+
+// layeredGraph returns the nodes of the resources, leaving the containers out, and the edges of the relationships
+// between them.
+func layeredGraph(resc *resources.ResourceCollection) ([]*layeredNode, []*layeredEdge) {
+	// The containers and the resources are looked up by ID, as the collection methods scan it on each call.
+	containers := make(map[string]struct{}, len(resc.Parents))
+	for _, parentID := range resc.Parents {
+		containers[parentID] = struct{}{}
+	}
+
+	ids := make(map[string]struct{}, len(resc.Resources))
+	for _, res := range resc.Resources {
+		ids[res.ID()] = struct{}{}
+	}
+
+	nodes := []*layeredNode{}
+	indexByID := map[string]int{}
+
+	for _, res := range resc.Resources {
+		if _, ok := indexByID[res.ID()]; ok {
+			continue
+		}
+
+		if _, ok := containers[res.ID()]; ok {
+			continue
+		}
+
+		indexByID[res.ID()] = len(nodes)
+		nodes = append(nodes, &layeredNode{id: res.ID(), groups: containersOf(resc.Parents, ids, res.ID())})
+	}
+
+	edges := []*layeredEdge{}
+
+	for i, rel := range resc.Relationships {
+		if rel.Source == nil || rel.Target == nil {
+			continue
+		}
+
+		from, okFrom := indexByID[rel.Source.ID()]
+		to, okTo := indexByID[rel.Target.ID()]
+
+		// Loops are left out, as they don't take part in the ranks.
+		if !okFrom || !okTo || from == to {
+			continue
+		}
+
+		edges = append(edges, &layeredEdge{relationship: i, from: from, to: to})
+	}
+
+	return nodes, edges
+}
+
+// containersOf returns the IDs of the containers of a resource that are in the collection, from the outermost one.
+// The depth is limited to stop on cyclic hierarchies.
+func containersOf(parents map[string]string, ids map[string]struct{}, id string) []string {
+	groups := []string{}
+
+	for depth := len(ids); depth > 0; depth-- {
+		parentID, ok := parents[id]
+		if !ok {
+			break
+		}
+
+		if _, ok := ids[parentID]; !ok {
+			break
+		}
+
+		groups = append(groups, parentID)
+		id = parentID
+	}
+
+	slices.Reverse(groups)
+
+	return groups
+}
+
+// breakCycles reverses the edges that close a cycle, found by a depth-first search in the order of the nodes.
+func breakCycles(nodes []*layeredNode, edges []*layeredEdge) {
+	outgoing := make([][]*layeredEdge, len(nodes))
+	for _, e := range edges {
+		outgoing[e.from] = append(outgoing[e.from], e)
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make([]int, len(nodes))
+
+	var visit func(n int)
+	visit = func(n int) {
+		state[n] = visiting
+
+		for _, e := range outgoing[n] {
+			switch state[e.to] {
+			case unvisited:
+				visit(e.to)
+			case visiting:
+				e.reversed = true
+			}
+		}
+
+		state[n] = visited
+	}
+
+	for n := range nodes {
+		if state[n] == unvisited {
+			visit(n)
+		}
+	}
+}
+
+// upper and lower return the ends of an edge in the upper and the lower rank.
+func (e *layeredEdge) upper() int {
+	if e.reversed {
+		return e.to
+	}
+
+	return e.from
+}
+
+func (e *layeredEdge) lower() int {
+	if e.reversed {
+		return e.from
+	}
+
+	return e.to
+}
+
+// assignRanks puts each node one rank below its lowest predecessor, then moves the nodes without predecessors down
+// to just above their closest successor, so the edges stay short.
+func assignRanks(nodes []*layeredNode, edges []*layeredEdge) {
+	predecessors := make([][]int, len(nodes))
+	successors := make([][]int, len(nodes))
+
+	for _, e := range edges {
+		predecessors[e.lower()] = append(predecessors[e.lower()], e.upper())
+		successors[e.upper()] = append(successors[e.upper()], e.lower())
+	}
+
+	order := topologicalOrder(predecessors, successors)
+
+	for _, n := range order {
+		for _, p := range predecessors[n] {
+			nodes[n].rank = max(nodes[n].rank, nodes[p].rank+1)
+		}
+	}
+
+	for i := len(order) - 1; i >= 0; i-- {
+		n := order[i]
+		if len(predecessors[n]) > 0 || len(successors[n]) == 0 {
+			continue
+		}
+
+		closest := nodes[successors[n][0]].rank
+		for _, s := range successors[n][1:] {
+			closest = min(closest, nodes[s].rank)
+		}
+
+		nodes[n].rank = closest - 1
+	}
+}
+
+// topologicalOrder returns the nodes of an acyclic graph with each node after its predecessors, ties kept in the
+// order of the nodes.
+func topologicalOrder(predecessors, successors [][]int) []int {
+	inDegree := make([]int, len(predecessors))
+	queue := []int{}
+
+	for n := range predecessors {
+		inDegree[n] = len(predecessors[n])
+		if inDegree[n] == 0 {
+			queue = append(queue, n)
+		}
+	}
+
+	order := make([]int, 0, len(predecessors))
+
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		order = append(order, n)
+
+		for _, s := range successors[n] {
+			inDegree[s]--
+			if inDegree[s] == 0 {
+				queue = append(queue, s)
+			}
+		}
+	}
+
+	return order
+}
+
+// insertDummyNodes links the nodes of each edge, through a dummy node in each rank the edge crosses.
+func insertDummyNodes(nodes []*layeredNode, edges []*layeredEdge) []*layeredNode {
+	for _, e := range edges {
+		upper, lower := e.upper(), e.lower()
+		e.chain = []int{upper}
+
+		for rank := nodes[upper].rank + 1; rank < nodes[lower].rank; rank++ {
+			nodes = append(nodes, &layeredNode{rank: rank, groups: commonGroups(nodes[upper], nodes[lower])})
+			e.chain = append(e.chain, len(nodes)-1)
+		}
+
+		e.chain = append(e.chain, lower)
+
+		for i := 1; i < len(e.chain); i++ {
+			nodes[e.chain[i-1]].down = append(nodes[e.chain[i-1]].down, e.chain[i])
+			nodes[e.chain[i]].up = append(nodes[e.chain[i]].up, e.chain[i-1])
+		}
+	}
+
+	return nodes
+}
+
+// commonGroups returns the containers shared by two nodes, so the dummy nodes of an edge inside a container stay in
+// it.
+func commonGroups(a, b *layeredNode) []string {
+	i := 0
+	for i < len(a.groups) && i < len(b.groups) && a.groups[i] == b.groups[i] {
+		i++
+	}
+
+	return a.groups[:i]
+}
+
+// orderRanks returns the nodes of each rank, ordered by the barycenter heuristic: each sweep sorts the nodes of a
+// rank by the mean position of their neighbors in the rank before it. The order with the fewest crossings is kept.
+func orderRanks(nodes []*layeredNode) [][]int {
+	rankCount := 0
+	for _, n := range nodes {
+		rankCount = max(rankCount, n.rank+1)
+	}
+
+	ranks := make([][]int, rankCount)
+	for i, n := range nodes {
+		ranks[n.rank] = append(ranks[n.rank], i)
+	}
+
+	// The nodes of each container start next to each other.
+	for _, rank := range ranks {
+		sortRank(nodes, rank, nil, func(*layeredNode) []int { return nil })
+	}
+
+	best := copyRanks(ranks)
+	bestCrossings := countCrossings(nodes, ranks)
+
+	for sweep := 0; sweep < orderingSweeps && bestCrossings > 0; sweep++ {
+		if sweep%2 == 0 {
+			for r := 1; r < len(ranks); r++ {
+				sortRank(nodes, ranks[r], positions(ranks[r-1]), func(n *layeredNode) []int { return n.up })
+			}
+		} else {
+			for r := len(ranks) - 2; r >= 0; r-- {
+				sortRank(nodes, ranks[r], positions(ranks[r+1]), func(n *layeredNode) []int { return n.down })
+			}
+		}
+
+		if crossings := countCrossings(nodes, ranks); crossings < bestCrossings {
+			best, bestCrossings = copyRanks(ranks), crossings
+		}
+	}
+
+	return best
+}
+
+func copyRanks(ranks [][]int) [][]int {
+	copied := make([][]int, len(ranks))
+	for i, rank := range ranks {
+		copied[i] = append([]int(nil), rank...)
+	}
+
+	return copied
+}
+
+// positions returns the position of each node of a rank.
+func positions(rank []int) map[int]int {
+	pos := make(map[int]int, len(rank))
+	for i, n := range rank {
+		pos[n] = i
+	}
+
+	return pos
+}
+
+// sortRank sorts the nodes of a rank by the barycenter of their neighbors in the adjacent rank, keeping the position
+// of the nodes without neighbors there. The nodes of a container are sorted together, by the mean barycenter of the
+// container.
+func sortRank(nodes []*layeredNode, rank []int, adjacent map[int]int, neighbors func(*layeredNode) []int) {
+	barycenters := make(map[int]float64, len(rank))
+	current := positions(rank)
+
+	for i, n := range rank {
+		barycenters[n] = float64(i)
+
+		if ns := neighbors(nodes[n]); len(ns) > 0 {
+			sum := 0.0
+			for _, neighbor := range ns {
+				sum += float64(adjacent[neighbor])
+			}
+
+			barycenters[n] = sum / float64(len(ns))
+		}
+	}
+
+	groupSums, groupCounts := map[string]float64{}, map[string]int{}
+
+	for _, n := range rank {
+		for _, group := range nodes[n].groups {
+			groupSums[group] += barycenters[n]
+			groupCounts[group]++
+		}
+	}
+
+	// key returns the barycenter of the node, or of its container, at a level of the containers.
+	key := func(n, level int) (float64, string) {
+		if groups := nodes[n].groups; level < len(groups) {
+			return groupSums[groups[level]] / float64(groupCounts[groups[level]]), groups[level]
+		}
+
+		return barycenters[n], ""
+	}
+
+	sort.SliceStable(rank, func(i, j int) bool {
+		a, b := rank[i], rank[j]
+
+		for level := 0; level <= max(len(nodes[a].groups), len(nodes[b].groups)); level++ {
+			keyA, groupA := key(a, level)
+			keyB, groupB := key(b, level)
+
+			if keyA != keyB {
+				return keyA < keyB
+			}
+
+			if groupA != groupB {
+				return groupA < groupB
+			}
+		}
+
+		return current[a] < current[b]
+	})
+}
+
+// countCrossings returns the number of crossings between the edges of each pair of adjacent ranks. The edges are
+// sorted by their upper end, so the crossings are the inversions of their lower ends, which a Fenwick tree counts.
+func countCrossings(nodes []*layeredNode, ranks [][]int) int {
+	crossings := 0
+
+	for r := 0; r+1 < len(ranks); r++ {
+		upperPos, lowerPos := positions(ranks[r]), positions(ranks[r+1])
+
+		type segment struct{ upper, lower int }
+
+		segments := []segment{}
+
+		for _, n := range ranks[r] {
+			for _, d := range nodes[n].down {
+				segments = append(segments, segment{upper: upperPos[n], lower: lowerPos[d]})
+			}
+		}
+
+		sort.Slice(segments, func(i, j int) bool {
+			if segments[i].upper != segments[j].upper {
+				return segments[i].upper < segments[j].upper
+			}
+
+			return segments[i].lower < segments[j].lower
+		})
+
+		// tree counts the lower ends of the segments already seen, by position.
+		tree := make([]int, len(ranks[r+1])+1)
+
+		for i, s := range segments {
+			notAfter := 0
+			for k := s.lower + 1; k > 0; k -= k & -k {
+				notAfter += tree[k]
+			}
+
+			// The segments seen before that end further right cross this one.
+			crossings += i - notAfter
+
+			for k := s.lower + 1; k < len(tree); k += k & -k {
+				tree[k]++
+			}
+		}
+	}
+
+	return crossings
+}
+
+// positionNodes places the nodes of each rank next to each other, then moves them towards the mean position of their
+// neighbors, alternating the neighbors above and below, without changing the order of the ranks.
+func (l *Layered) positionNodes(nodes []*layeredNode, ranks [][]int) {
+	nodeSep := l.nodeSep()
+
+	for _, rank := range ranks {
+		for i, n := range rank {
+			if i > 0 {
+				nodes[n].x = nodes[rank[i-1]].x + separation(nodes[rank[i-1]], nodes[n], nodeSep)
+			}
+		}
+	}
+
+	for pass := 0; pass < positioningPasses; pass++ {
+		neighbors := func(n *layeredNode) []int { return n.up }
+		if pass%2 == 1 {
+			neighbors = func(n *layeredNode) []int { return n.down }
+		}
+
+		for _, rank := range ranks {
+			placeRank(nodes, rank, neighbors, nodeSep)
+		}
+	}
+
+	separateClusters(nodes, ranks, nodeSep)
+
+	minX := 0.0
+	for i, n := range nodes {
+		if i == 0 || n.x-n.width()/2 < minX {
+			minX = n.x - n.width()/2
+		}
+	}
+
+	for _, n := range nodes {
+		n.x -= minX
+	}
+}
+
+// placeRank moves the nodes of a rank as close as possible to the mean position of their neighbors, keeping them
+// apart. The nodes are packed from the left and from the right, and placed in the middle of both.
+func placeRank(nodes []*layeredNode, rank []int, neighbors func(*layeredNode) []int, nodeSep float64) {
+	desired := make([]float64, len(rank))
+
+	for i, n := range rank {
+		desired[i] = nodes[n].x
+
+		if ns := neighbors(nodes[n]); len(ns) > 0 {
+			sum := 0.0
+			for _, neighbor := range ns {
+				sum += nodes[neighbor].x
+			}
+
+			desired[i] = sum / float64(len(ns))
+		}
+	}
+
+	left, right := make([]float64, len(rank)), make([]float64, len(rank))
+
+	for i := range rank {
+		left[i] = desired[i]
+		if i > 0 {
+			left[i] = max(desired[i], left[i-1]+separation(nodes[rank[i-1]], nodes[rank[i]], nodeSep))
+		}
+	}
+
+	for i := len(rank) - 1; i >= 0; i-- {
+		right[i] = desired[i]
+		if i < len(rank)-1 {
+			right[i] = min(desired[i], right[i+1]-separation(nodes[rank[i]], nodes[rank[i+1]], nodeSep))
+		}
+	}
+
+	for i, n := range rank {
+		nodes[n].x = (left[i] + right[i]) / 2
+	}
+}
+
+// separateClusters moves the nodes out of the area of the containers they are not in, which spans the ranks of the
+// container children, shifting the rest of their ranks with them. Inner containers are separated first.
+func separateClusters(nodes []*layeredNode, ranks [][]int, nodeSep float64) {
+	members := map[string][]int{}
+	depths := map[string]int{}
+
+	for i, n := range nodes {
+		for depth, group := range n.groups {
+			members[group] = append(members[group], i)
+			depths[group] = depth
+		}
+	}
+
+	groups := make([]string, 0, len(members))
+	for group := range members {
+		groups = append(groups, group)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if depths[groups[i]] != depths[groups[j]] {
+			return depths[groups[i]] > depths[groups[j]]
+		}
+
+		return groups[i] < groups[j]
+	})
+
+	// Moving a node out of a container can move others into another one, so the passes go on until nothing moves.
+	for pass := 0; pass <= len(groups); pass++ {
+		moved := false
+
+		for _, group := range groups {
+			if separateCluster(nodes, ranks, group, members[group], nodeSep) {
+				moved = true
+			}
+		}
+
+		if !moved {
+			return
+		}
+	}
+}
+
+// separateCluster moves the nodes out of the area of a container, to the side of the container they are ordered on.
+// It reports whether any node moved.
+func separateCluster(nodes []*layeredNode, ranks [][]int, group string, members []int, nodeSep float64) bool {
+	first := nodes[members[0]]
+	left, right := first.x-first.width()/2, first.x+first.width()/2
+	minRank, maxRank := first.rank, first.rank
+
+	for _, m := range members[1:] {
+		n := nodes[m]
+		left, right = min(left, n.x-n.width()/2), max(right, n.x+n.width()/2)
+		minRank, maxRank = min(minRank, n.rank), max(maxRank, n.rank)
+	}
+
+	left -= clusterPadding + nodeSep
+	right += clusterPadding + nodeSep
+
+	moved := false
+
+	for r := minRank; r <= maxRank; r++ {
+		rank := ranks[r]
+		firstMember, lastMember := -1, -1
+
+		for i, n := range rank {
+			if isMember(nodes[n], group) {
+				if firstMember < 0 {
+					firstMember = i
+				}
+
+				lastMember = i
+			}
+		}
+
+		for i, n := range rank {
+			node := nodes[n]
+			if isMember(node, group) || node.x+node.width()/2 <= left || node.x-node.width()/2 >= right {
+				continue
+			}
+
+			toLeft := i < firstMember
+			if firstMember < 0 || (i > firstMember && i < lastMember) {
+				toLeft = node.x < (left+right)/2
+			}
+
+			if toLeft {
+				delta := node.x + node.width()/2 - left
+				for _, before := range rank[:i+1] {
+					nodes[before].x -= delta
+				}
+			} else {
+				delta := right - (node.x - node.width()/2)
+				for _, after := range rank[i:] {
+					nodes[after].x += delta
+				}
+			}
+
+			moved = true
+		}
+	}
+
+	return moved
+}
+
+func isMember(n *layeredNode, group string) bool {
+	for _, g := range n.groups {
+		if g == group {
+			return true
+		}
+	}
+
+	return false
+}
+
+// separation returns the distance between the centers of two adjacent nodes of a rank, with room for the borders of
+// the containers between them.
+func separation(a, b *layeredNode, nodeSep float64) float64 {
+	common := len(commonGroups(a, b))
+	borders := len(a.groups) + len(b.groups) - 2*common
+
+	return (a.width()+b.width())/2 + nodeSep + float64(borders)*clusterPadding
+}
+
+// result returns the boxes of the resources and the routes of the relationships, turned to the configured direction.
+func (l *Layered) result(
+	resc *resources.ResourceCollection, nodes []*layeredNode, edges []*layeredEdge, rankCount int,
+) *Layout {
+	depth := 0
+	for _, n := range nodes {
+		depth = max(depth, len(n.groups))
+	}
+
+	// The ranks leave room for the padding below and above the containers, and for their labels.
+	rankStep := NodeSize + l.rankSep() + float64(3*clusterPadding*depth)
+	height := float64(max(rankCount-1, 0))*rankStep + NodeSize
+
+	width := 0.0
+	for _, n := range nodes {
+		width = max(width, n.x+n.width()/2)
+	}
+
+	center := func(n *layeredNode) Point {
+		return Point{X: n.x, Y: float64(n.rank)*rankStep + NodeSize/2}
+	}
+
+	turn := l.direction(width, height)
+
+	layout := &Layout{Nodes: map[string]Rect{}, Edges: make([]Edge, len(resc.Relationships))}
+
+	for _, n := range nodes {
+		if n.id == "" {
+			continue
+		}
+
+		c := turn(center(n))
+		layout.Nodes[n.id] = Rect{X: c.X - NodeSize/2, Y: c.Y - NodeSize/2, Width: NodeSize, Height: NodeSize}
+	}
+
+	for _, e := range edges {
+		points := make([]Point, 0, len(e.chain))
+
+		for i, n := range e.chain {
+			p := center(nodes[n])
+
+			// The edge leaves the bottom of the upper node and reaches the top of the lower one.
+			switch i {
+			case 0:
+				p.Y += NodeSize / 2
+			case len(e.chain) - 1:
+				p.Y -= NodeSize / 2
+			}
+
+			points = append(points, turn(p))
+		}
+
+		if e.reversed {
+			for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+				points[i], points[j] = points[j], points[i]
+			}
+		}
+
+		layout.Edges[e.relationship] = Edge{Points: points}
+	}
+
+	return layout
+}
+
+// direction returns the function turning a point of the ranks laid out from top to bottom to the configured
+// direction, within a layout of the given width and height.
+func (l *Layered) direction(width, height float64) func(Point) Point {
+	switch l.config.Direction {
+	case dot.DirectionBottomToTop:
+		return func(p Point) Point { return Point{X: p.X, Y: height - p.Y} }
+	case dot.DirectionLeftToRight:
+		return func(p Point) Point { return Point{X: p.Y, Y: p.X} }
+	case dot.DirectionRightToLeft:
+		return func(p Point) Point { return Point{X: height - p.Y, Y: p.X} }
+	default:
+		return func(p Point) Point { return p }
+	}
+}
+
+func (l *Layered) nodeSep() float64 {
+	if l.config.NodeSep > 0 {
+		return l.config.NodeSep * pointsPerInch
+	}
+
+	return defaultNodeSep * pointsPerInch
+}
+
+func (l *Layered) rankSep() float64 {
+	if l.config.RankSep > 0 {
+		return l.config.RankSep * pointsPerInch
+	}
+
+	return defaultRankSep * pointsPerInch
+}
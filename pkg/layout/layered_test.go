@@ -0,0 +1,220 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/diagram-code-generator/resources/pkg/parser/graphviz/dot"
+	"github.com/diagram-code-generator/resources/pkg/resources"
+)
+
+func TestLayered_Layout(t *testing.T) {
+	a := resources.NewGenericResource("a", "MyAPI", "apigateway")
+	b := resources.NewGenericResource("b", "MyLambda", "lambda")
+	c := resources.NewGenericResource("c", "my-queue", "sqs")
+
+	chain := &resources.ResourceCollection{
+		Resources:     []resources.Resource{a, b},
+		Relationships: []resources.Relationship{{Source: a, Target: b}},
+	}
+
+	tests := []struct {
+		name   string
+		config *Config
+		resc   *resources.ResourceCollection
+		want   *Layout
+	}{
+		{
+			name: "top to bottom by default",
+			resc: chain,
+			want: &Layout{
+				Nodes: map[string]Rect{
+					"a": {X: 0, Y: 0, Width: 40, Height: 40},
+					"b": {X: 0, Y: 76, Width: 40, Height: 40},
+				},
+				Edges: []Edge{{Points: []Point{{X: 20, Y: 40}, {X: 20, Y: 76}}}},
+			},
+		},
+		{
+			name:   "bottom to top",
+			config: &Config{Direction: dot.DirectionBottomToTop},
+			resc:   chain,
+			want: &Layout{
+				Nodes: map[string]Rect{
+					"a": {X: 0, Y: 76, Width: 40, Height: 40},
+					"b": {X: 0, Y: 0, Width: 40, Height: 40},
+				},
+				Edges: []Edge{{Points: []Point{{X: 20, Y: 76}, {X: 20, Y: 40}}}},
+			},
+		},
+		{
+			name:   "left to right",
+			config: &Config{Direction: dot.DirectionLeftToRight},
+			resc:   chain,
+			want: &Layout{
+				Nodes: map[string]Rect{
+					"a": {X: 0, Y: 0, Width: 40, Height: 40},
+					"b": {X: 76, Y: 0, Width: 40, Height: 40},
+				},
+				Edges: []Edge{{Points: []Point{{X: 40, Y: 20}, {X: 76, Y: 20}}}},
+			},
+		},
+		{
+			name:   "right to left",
+			config: &Config{Direction: dot.DirectionRightToLeft},
+			resc:   chain,
+			want: &Layout{
+				Nodes: map[string]Rect{
+					"a": {X: 76, Y: 0, Width: 40, Height: 40},
+					"b": {X: 0, Y: 0, Width: 40, Height: 40},
+				},
+				Edges: []Edge{{Points: []Point{{X: 76, Y: 20}, {X: 40, Y: 20}}}},
+			},
+		},
+		{
+			name:   "node and rank separation in inches",
+			config: &Config{NodeSep: 1, RankSep: 1},
+			resc: &resources.ResourceCollection{
+				Resources:     []resources.Resource{a, b, c},
+				Relationships: []resources.Relationship{{Source: a, Target: b}, {Source: a, Target: c}},
+			},
+			want: &Layout{
+				Nodes: map[string]Rect{
+					"a": {X: 56, Y: 0, Width: 40, Height: 40},
+					"b": {X: 0, Y: 112, Width: 40, Height: 40},
+					"c": {X: 112, Y: 112, Width: 40, Height: 40},
+				},
+				Edges: []Edge{
+					{Points: []Point{{X: 76, Y: 40}, {X: 20, Y: 112}}},
+					{Points: []Point{{X: 76, Y: 40}, {X: 132, Y: 112}}},
+				},
+			},
+		},
+		{
+			name: "cycles are broken and loops are not routed",
+			resc: &resources.ResourceCollection{
+				Resources: []resources.Resource{a, b},
+				Relationships: []resources.Relationship{
+					{Source: a, Target: b}, {Source: b, Target: a}, {Source: b, Target: b},
+				},
+			},
+			want: &Layout{
+				Nodes: map[string]Rect{
+					"a": {X: 0, Y: 0, Width: 40, Height: 40},
+					"b": {X: 0, Y: 76, Width: 40, Height: 40},
+				},
+				Edges: []Edge{
+					{Points: []Point{{X: 20, Y: 40}, {X: 20, Y: 76}}},
+					{Points: []Point{{X: 20, Y: 76}, {X: 20, Y: 40}}},
+					{},
+				},
+			},
+		},
+	}
+
+	for i := range tests {
+		tc := tests[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := NewLayered(tc.config).Layout(tc.resc)
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestLayered_Layout_LongEdges(t *testing.T) {
+	a := resources.NewGenericResource("a", "MyAPI", "apigateway")
+	b := resources.NewGenericResource("b", "MyLambda", "lambda")
+	c := resources.NewGenericResource("c", "my-queue", "sqs")
+
+	got, err := NewLayered(nil).Layout(&resources.ResourceCollection{
+		Resources: []resources.Resource{a, b, c},
+		Relationships: []resources.Relationship{
+			{Source: a, Target: b}, {Source: b, Target: c}, {Source: a, Target: c},
+		},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, got.Edges[1].Points, 2)
+
+	// The edge spanning two ranks bends around b in the middle rank.
+	require.Len(t, got.Edges[2].Points, 3)
+	require.Equal(t, got.Nodes["b"].Y+NodeSize/2, got.Edges[2].Points[1].Y)
+	require.Greater(t, got.Edges[2].Points[1].X, got.Nodes["b"].X+NodeSize)
+}
+
+func TestLayered_Layout_CrossingReduction(t *testing.T) {
+	a1 := resources.NewGenericResource("a1", "a1", "lambda")
+	a2 := resources.NewGenericResource("a2", "a2", "lambda")
+	b1 := resources.NewGenericResource("b1", "b1", "sqs")
+	b2 := resources.NewGenericResource("b2", "b2", "sqs")
+
+	got, err := NewLayered(nil).Layout(&resources.ResourceCollection{
+		Resources:     []resources.Resource{a1, a2, b1, b2},
+		Relationships: []resources.Relationship{{Source: a1, Target: b2}, {Source: a2, Target: b1}},
+	})
+
+	require.NoError(t, err)
+	require.Less(t, got.Nodes["a1"].X, got.Nodes["a2"].X)
+	require.Less(t, got.Nodes["b2"].X, got.Nodes["b1"].X)
+}
+
+func TestLayered_Layout_Containers(t *testing.T) {
+	a := resources.NewGenericResource("a", "MyAPI", "apigateway")
+	b := resources.NewGenericResource("b", "MyLambda", "lambda")
+	c := resources.NewGenericResource("c", "my-queue", "sqs")
+	d := resources.NewGenericResource("d", "MyTable", "dynamodb")
+	vpc := resources.NewGenericResource("vpc", "vpc", "vpc")
+
+	got, err := NewLayered(nil).Layout(&resources.ResourceCollection{
+		Resources: []resources.Resource{vpc, a, b, c, d},
+		Relationships: []resources.Relationship{
+			{Source: a, Target: b}, {Source: a, Target: c}, {Source: b, Target: d}, {Source: vpc, Target: a},
+		},
+		Parents: map[string]string{b.ID(): vpc.ID(), d.ID(): vpc.ID()},
+	})
+
+	require.NoError(t, err)
+	require.NotContains(t, got.Nodes, vpc.ID())
+	require.Empty(t, got.Edges[3].Points)
+
+	// c is kept out of the area of the vpc, around b and d.
+	left := min(got.Nodes["b"].X, got.Nodes["d"].X) - clusterPadding
+	right := max(got.Nodes["b"].X, got.Nodes["d"].X) + NodeSize + clusterPadding
+
+	require.True(t, got.Nodes["c"].X+NodeSize <= left || got.Nodes["c"].X >= right, "c overlaps the vpc: %+v", got)
+}
+
+func TestCountCrossings(t *testing.T) {
+	tests := []struct {
+		name string
+		// edges link the nodes of the upper rank, 0 to 3, to the ones of the lower rank, 4 to 7.
+		edges [][2]int
+		want  int
+	}{
+		{name: "no edges", want: 0},
+		{name: "parallel edges", edges: [][2]int{{0, 4}, {1, 5}, {2, 6}}, want: 0},
+		{name: "crossing edges", edges: [][2]int{{0, 5}, {1, 4}}, want: 1},
+		{name: "edges sharing an end", edges: [][2]int{{0, 4}, {0, 5}, {1, 5}}, want: 0},
+		{name: "complete bipartite graph", edges: [][2]int{{0, 4}, {0, 5}, {1, 4}, {1, 5}}, want: 1},
+		{name: "reversed edges", edges: [][2]int{{0, 7}, {1, 6}, {2, 5}, {3, 4}}, want: 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes := make([]*layeredNode, 8)
+			for i := range nodes {
+				nodes[i] = &layeredNode{}
+			}
+
+			for _, e := range tt.edges {
+				nodes[e[0]].down = append(nodes[e[0]].down, e[1])
+			}
+
+			require.Equal(t, tt.want, countCrossings(nodes, [][]int{{0, 1, 2, 3}, {4, 5, 6, 7}}))
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// Package layout computes the positions of the resources of a resources.ResourceCollection and the routes of their
+// relationships, for the diagram formats that store coordinates, such as draw.io.
+//
+// Layered is a pure Go layered layout, so diagrams can be laid out without cgo. Builds with cgo can also lay them out
+// with Graphviz, which New picks for them.
+package layout
+
+import (
+	"github.com/diagram-code-generator/resources/pkg/parser/graphviz/dot"
+	"github.com/diagram-code-generator/resources/pkg/resources"
+)
+
+// NodeSize is the width and height, in points, of the box laid out for each resource.
+const NodeSize = 40
+
+const (
+	// pointsPerInch converts the separations of the Config to points.
+	pointsPerInch = 72.0
+	// defaultNodeSep and defaultRankSep are the Graphviz default separations, in inches.
+	defaultNodeSep = 0.25
+	defaultRankSep = 0.5
+)
+
+// Layouter lays out the resources of a collection. Containers are not laid out, as they are drawn around their
+// children.
+type Layouter interface {
+	Layout(resc *resources.ResourceCollection) (*Layout, error)
+}
+
+// Config holds the options of a Layouter. Empty fields keep the Graphviz defaults.
+type Config struct {
+	// Engine is the Graphviz layout engine, e.g. dot.EngineNeato. The layered layout ignores it.
+	Engine    dot.Engine
+	Direction dot.DiagramDirection
+	// NodeSep and RankSep are the space between the nodes of a rank and between the ranks, in inches.
+	NodeSep float64
+	RankSep float64
+}
+
+// Point is a position, in points.
+type Point struct {
+	X, Y float64
+}
+
+// Rect is the box of a node, in points.
+type Rect struct {
+	X, Y, Width, Height float64
+}
+
+// Edge is the route of a relationship, as a polyline from the source to the target. Relationships that were not laid
+// out, e.g. the ones of containers, have no points.
+type Edge struct {
+	Points []Point
+}
+
+// Layout holds the boxes of the resources, by ID, and the routes of the relationships, in the order of the
+// collection Relationships. All the layouters use the draw.io coordinates: X grows to the right and Y grows down, from
+// the leftmost and topmost of the boxes and route points, which are at 0.
+type Layout struct {
+	Nodes map[string]Rect
+	Edges []Edge
+}
+
+// normalize moves the boxes and the routes so that the leftmost and topmost of them are at 0.
+func (l *Layout) normalize() {
+	var (
+		minX, minY float64
+		first      = true
+	)
+
+	visit := func(x, y float64) {
+		if first || x < minX {
+			minX = x
+		}
+
+		if first || y < minY {
+			minY = y
+		}
+
+		first = false
+	}
+
+	for _, r := range l.Nodes {
+		visit(r.X, r.Y)
+	}
+
+	for _, e := range l.Edges {
+		for _, p := range e.Points {
+			visit(p.X, p.Y)
+		}
+	}
+
+	for id, r := range l.Nodes {
+		r.X, r.Y = r.X-minX, r.Y-minY
+		l.Nodes[id] = r
+	}
+
+	for _, e := range l.Edges {
+		for i := range e.Points {
+			e.Points[i].X, e.Points[i].Y = e.Points[i].X-minX, e.Points[i].Y-minY
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/diagram-code-generator/resources/pkg/resources"
+)
+
+// TestLayout_Coordinates checks that the layered layout and the default one, Graphviz in the builds with cgo, both put
+// the top left of the layout at 0 with Y growing down.
+func TestLayout_Coordinates(t *testing.T) {
+	a := resources.NewGenericResource("a", "MyAPI", "apigateway")
+	b := resources.NewGenericResource("b", "MyLambda", "lambda")
+	resc := &resources.ResourceCollection{
+		Resources:     []resources.Resource{a, b},
+		Relationships: []resources.Relationship{{Source: a, Target: b}},
+	}
+
+	tests := []struct {
+		name     string
+		layouter Layouter
+	}{
+		{name: "layered layout", layouter: NewLayered(nil)},
+		{name: "default layout", layouter: New(nil)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.layouter.Layout(resc)
+			require.NoError(t, err)
+
+			minX, minY := got.Nodes["a"].X, got.Nodes["a"].Y
+
+			for _, r := range got.Nodes {
+				require.GreaterOrEqual(t, r.X, 0.0)
+				require.GreaterOrEqual(t, r.Y, 0.0)
+				minX, minY = min(minX, r.X), min(minY, r.Y)
+			}
+
+			for _, p := range got.Edges[0].Points {
+				require.GreaterOrEqual(t, p.X, 0.0)
+				require.GreaterOrEqual(t, p.Y, 0.0)
+				minX, minY = min(minX, p.X), min(minY, p.Y)
+			}
+
+			require.Zero(t, minX)
+			require.Zero(t, minY)
+
+			// The source is ranked above the target, and the edge goes down from it.
+			require.Less(t, got.Nodes["a"].Y, got.Nodes["b"].Y)
+
+			points := got.Edges[0].Points
+			require.Less(t, points[0].Y, points[len(points)-1].Y)
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package dot
+
+import "github.com/diagram-code-generator/resources/pkg/resources"
+
+// ClusterFunc returns the group of a resource. Resources of the same group are drawn inside the same
+// "subgraph cluster_*" block; an empty group leaves the resource outside of any cluster.
+type ClusterFunc func(res resources.Resource) string
+
+// ClusterStyle holds the label and the DOT attributes of a cluster, e.g. {"style": "filled", "fillcolor": "#eeeeee"}.
+type ClusterStyle struct {
+	Label string
+	Attrs map[string]any
+}
+
+// ClusterByType groups the resources by their resource type.
+func ClusterByType(res resources.Resource) string {
+	return res.ResourceType()
+}
+
+// clusterKeyPrefix keeps the groups apart from the container clusters, which are keyed by resource ID.
+const clusterKeyPrefix = "group:"
@@ -5,6 +5,13 @@ import "github.com/diagram-code-generator/resources/pkg/resources"
 type Style struct {
 	Nodes  map[resources.Resource]string
 	Arrows map[string][]map[string]string
+
+	// NodeRules and EdgeRules set any DOT attribute on the nodes and edges they match. The attributes are set after
+	// the ones from the Config maps, with the most specific rules last (by ID, value, attributes and then type, and
+	// by relationship kind or label before the endpoints, ignoring patterns like "*" that match everything), and
+	// before the Nodes font colors and Arrows colors.
+	NodeRules []NodeRule
+	EdgeRules []EdgeRule
 }
 
 type Config struct {
@@ -14,4 +21,51 @@ type Config struct {
 	EdgeAttrs        map[string]any
 	ResourceImageMap map[string]string
 	Style            *Style
+	// NodeKey identifies the node of each resource; the resource value is only its label. Defaults to NodeKeyByID.
+	// Style.Arrows names the nodes by key or by resource value.
+	NodeKey NodeKeyFunc
+	// ResourceIDs writes the resource ID of each node and container cluster in its ResourceIDAttr attribute, so
+	// dottoresources reads the resources back with their IDs.
+	ResourceIDs bool
+
+	// Graph attributes, set when not empty: https://graphviz.org/docs/graph/.
+	NodeSep     float64
+	RankSep     float64
+	Concentrate bool
+	Compound    bool
+	NewRank     bool
+	Label       string
+	LabelLoc    string
+	BgColor     string
+	FontName    string
+	FontSize    float64
+	Size        string
+	Ratio       string
+	// GraphAttrs holds any other graph attribute. The fields above take precedence.
+	GraphAttrs map[string]any
+	// Ranks holds the rank constraints of the nodes. A resource follows the first constraint it matches.
+	Ranks []RankConstraint
+
+	// Theme holds the graph, node, edge and cluster attributes and the images shared with other diagrams, e.g. a
+	// preset from ThemeByName. The attributes of the Config take precedence.
+	Theme *Theme
+	// Legend adds a cluster listing the resource types and the edge styles of the diagram.
+	Legend *Legend
+
+	// ResourceAttrMap maps resource attribute keys to DOT node attribute names, e.g. {"description": "tooltip"}.
+	ResourceAttrMap map[string]string
+	// RelationshipAttrMap maps relationship attribute keys to DOT edge attribute names.
+	RelationshipAttrMap map[string]string
+	// EdgeKindAttrs holds the DOT edge attributes applied to the relationships of each kind.
+	EdgeKindAttrs map[string]map[string]any
+	// EdgeStyle returns the rendering options of each relationship: labels, head and tail labels, line style,
+	// arrows, ports and constraint. They are set after EdgeKindAttrs, RelationshipAttrMap and the relationship label.
+	EdgeStyle EdgeStyleFunc
+
+	// Cluster groups the top-level resources into "subgraph cluster_*" blocks, e.g. ClusterByType. Resources inside
+	// containers stay in the cluster of their container.
+	Cluster ClusterFunc
+	// ClusterStyles holds the label and attributes of each group returned by Cluster. Groups without a style are
+	// labeled with the group name.
+	ClusterStyles map[string]ClusterStyle
 }
@@ -0,0 +1,82 @@
+package dot
+
+import (
+	"html"
+
+	"github.com/emicklei/dot"
+
+	"github.com/diagram-code-generator/resources/pkg/resources"
+)
+
+// DiffColors holds the colors of the added, removed and modified nodes and edges drawn by BuildDiff.
+var DiffColors = map[resources.DiffStatus]string{
+	resources.DiffAdded:    "#2e7d32",
+	resources.DiffRemoved:  "#c62828",
+	resources.DiffModified: "#ff8f00",
+}
+
+// BuildDiff returns the DOT text of the union of two resource collections, built by resources.DiffCollection. Added
+// nodes and edges are drawn in green, removed ones in red and struck through, and modified ones in amber, as set in
+// DiffColors.
+func (d *DotDiagram) BuildDiff(before, after *resources.ResourceCollection) string {
+	d.diff = true
+	defer func() { d.diff = false }()
+
+	return d.build(resources.DiffCollection(before, after))
+}
+
+// applyDiffNodeStyle sets the colors of the node of a resource of a diff.
+func (d *DotDiagram) applyDiffNodeStyle(node dot.Node, res resources.Resource) dot.Node {
+	status := resources.DiffStatusOf(res)
+	if !d.diff || status == resources.DiffUnchanged {
+		return node
+	}
+
+	node = node.Attr("color", DiffColors[status]).Attr("fontcolor", DiffColors[status])
+
+	if status == resources.DiffRemoved {
+		node = node.Attr("label", strikethrough(res.Value()))
+	}
+
+	return node
+}
+
+// applyDiffClusterStyle sets the colors of the cluster of a container of a diff.
+func (d *DotDiagram) applyDiffClusterStyle(cluster *dot.Graph, container resources.Resource) {
+	status := resources.DiffStatusOf(container)
+	if !d.diff || status == resources.DiffUnchanged {
+		return
+	}
+
+	cluster.Attr("color", DiffColors[status])
+	cluster.Attr("fontcolor", DiffColors[status])
+
+	if status == resources.DiffRemoved {
+		cluster.Attr("label", strikethrough(container.Value()))
+	}
+}
+
+// applyDiffEdgeStyle sets the colors of the edge of a relationship of a diff.
+func (d *DotDiagram) applyDiffEdgeStyle(edge dot.Edge, rel resources.Relationship) dot.Edge {
+	status := resources.RelationshipDiffStatus(rel)
+	if !d.diff || status == resources.DiffUnchanged {
+		return edge
+	}
+
+	edge = edge.Attr("color", DiffColors[status]).Attr("fontcolor", DiffColors[status])
+
+	if status == resources.DiffRemoved {
+		edge = edge.Attr("style", "dashed")
+
+		if rel.Label != "" {
+			edge = edge.Attr("label", strikethrough(rel.Label))
+		}
+	}
+
+	return edge
+}
+
+// strikethrough returns an HTML label with the text struck through.
+func strikethrough(text string) dot.HTML {
+	return dot.HTML("<s>" + html.EscapeString(text) + "</s>")
+}
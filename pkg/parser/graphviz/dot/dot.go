@@ -1,6 +1,10 @@
 package dot
 
 import (
+	"errors"
+	"fmt"
+	"sort"
+
 	"github.com/emicklei/dot"
 
 	"github.com/diagram-code-generator/resources/pkg/resources"
@@ -20,9 +24,21 @@ var (
 	}
 )
 
+var (
+	ErrUnknownStyleReference = errors.New("style references an unknown node")
+	ErrDuplicateNodeKey      = errors.New("duplicate node key")
+)
+
 type DotDiagram struct {
 	config *Config
 	g      *dot.Graph
+
+	// errs holds the problems found by the last build.
+	errs []error
+	// diff is set while BuildDiff draws the statuses of a diff.
+	diff bool
+	// keysByValue resolves the resource values used by Style.Arrows to node keys.
+	keysByValue map[string]string
 }
 
 func NewDotDiagram(config *Config) *DotDiagram {
@@ -32,7 +48,31 @@ func NewDotDiagram(config *Config) *DotDiagram {
 	}
 }
 
+// Build returns the DOT text of the resource collection. Broken relationships and Style.Arrows references are skipped,
+// and Style.Nodes resources that are not in the collection are drawn as standalone nodes; use BuildE to get them as an
+// error.
 func (d *DotDiagram) Build(resc *resources.ResourceCollection) string {
+	return d.build(resc)
+}
+
+// BuildE returns the DOT text of the resource collection, or an error joining every problem found: relationships
+// with nil endpoints or endpoints that are not in the collection, resources sharing the same node key, which would be
+// drawn as a single node, and Style.Nodes and Style.Arrows entries referencing resources or nodes that are not in the
+// collection.
+func (d *DotDiagram) BuildE(resc *resources.ResourceCollection) (string, error) {
+	text := d.build(resc)
+
+	if err := errors.Join(d.errs...); err != nil {
+		return "", err
+	}
+
+	return text, nil
+}
+
+func (d *DotDiagram) build(resc *resources.ResourceCollection) string {
+	d.errs = nil
+	d.keysByValue = map[string]string{}
+
 	if d.config == nil {
 		d.config = defaultConfig()
 	}
@@ -41,41 +81,52 @@ func (d *DotDiagram) Build(resc *resources.ResourceCollection) string {
 
 	config := d.config
 
-	d.g.NodeInitializer(func(n dot.Node) {
-		var nodeAttrs map[string]any = DefaultNodeAttrs
+	theme := config.Theme
+	if theme == nil {
+		theme = &Theme{}
+	}
 
-		if len(config.NodeAttrs) > 0 {
-			nodeAttrs = config.NodeAttrs
-		}
+	nodeAttrs := elementAttrs(DefaultNodeAttrs, config.NodeAttrs, theme.NodeAttrs)
+	edgeAttrs := elementAttrs(DefaultEdgeAttrs, config.EdgeAttrs, theme.EdgeAttrs)
 
-		for name, value := range nodeAttrs {
-			n.Attrs(name, value)
+	d.g.NodeInitializer(func(n dot.Node) {
+		for _, name := range sortedKeys(nodeAttrs) {
+			n.Attrs(name, nodeAttrs[name])
 		}
 	})
 
 	d.g.EdgeInitializer(func(e dot.Edge) {
-		var edgeAttrs map[string]any = DefaultEdgeAttrs
-
-		if len(config.EdgeAttrs) > 0 {
-			edgeAttrs = config.EdgeAttrs
-		}
-
-		for name, value := range edgeAttrs {
-			e.Attrs(name, value)
+		for _, name := range sortedKeys(edgeAttrs) {
+			e.Attrs(name, edgeAttrs[name])
 		}
 	})
 
 	nodes := map[string]dot.Node{}
 	edges := map[string]struct{}{}
+	clusters := map[string]*dot.Graph{}
+
+	d.applyStyleForNodes(resc, nodes, clusters)
 
-	d.applyStyleForNodes(resc, nodes)
+	d.applyStyleForArrows(resc, edges, nodes, clusters)
 
-	d.applyStyleForArrows(resc, edges, nodes)
+	d.applyLegend(resc)
 
 	return d.g.String()
 }
 
 func (d *DotDiagram) applyStyleForDiagram() {
+	if d.config.Theme != nil {
+		for _, k := range sortedKeys(d.config.Theme.GraphAttrs) {
+			d.g.Attr(k, d.config.Theme.GraphAttrs[k])
+		}
+	}
+
+	for _, k := range sortedKeys(d.config.GraphAttrs) {
+		d.g.Attr(k, d.config.GraphAttrs[k])
+	}
+
+	d.applyGraphAttrs()
+
 	if d.config.Direction != "" {
 		d.g.Attr("rankdir", d.config.Direction)
 	}
@@ -85,91 +136,407 @@ func (d *DotDiagram) applyStyleForDiagram() {
 	}
 }
 
-func (d *DotDiagram) applyStyleForNodes(resc *resources.ResourceCollection, nodes map[string]dot.Node) {
+// applyGraphAttrs sets the graph attributes of the Config fields that are not empty.
+func (d *DotDiagram) applyGraphAttrs() {
+	config := d.config
+
+	for _, attr := range []struct {
+		key   string
+		value any
+		isSet bool
+	}{
+		{"nodesep", config.NodeSep, config.NodeSep != 0},
+		{"ranksep", config.RankSep, config.RankSep != 0},
+		{"concentrate", "true", config.Concentrate},
+		{"compound", "true", config.Compound},
+		{"newrank", "true", config.NewRank},
+		{"label", config.Label, config.Label != ""},
+		{"labelloc", config.LabelLoc, config.LabelLoc != ""},
+		{"bgcolor", config.BgColor, config.BgColor != ""},
+		{"fontname", config.FontName, config.FontName != ""},
+		{"fontsize", config.FontSize, config.FontSize != 0},
+		{"size", config.Size, config.Size != ""},
+		{"ratio", config.Ratio, config.Ratio != ""},
+	} {
+		if attr.isSet {
+			d.g.Attr(attr.key, attr.value)
+		}
+	}
+}
+
+func (d *DotDiagram) applyStyleForNodes(
+	resc *resources.ResourceCollection, nodes map[string]dot.Node, clusters map[string]*dot.Graph,
+) {
 	style := d.config.Style
 	if style == nil {
 		style = &Style{}
 	}
 
-	resourceImageMap := d.config.ResourceImageMap
+	idsByKey := map[string]string{}
+	nodeRules := sortNodeRules(style.NodeRules)
 
 	for i := range resc.Resources {
 		res := resc.Resources[i]
-		node := d.g.Node(res.Value())
+		key := d.nodeKey(res)
 
-		if resourceImageMap != nil {
-			if image, ok := resourceImageMap[res.ResourceType()]; ok {
-				node = node.Attr("image", image)
-			}
+		if id, ok := idsByKey[key]; ok {
+			d.errs = append(d.errs, fmt.Errorf("%w: %q is used by the resources %s and %s",
+				ErrDuplicateNodeKey, key, id, res.ID()))
+		}
+
+		idsByKey[key] = res.ID()
+
+		// Containers are drawn as the clusters around their children instead of nodes.
+		if resc.IsContainer(res) {
+			continue
+		}
+
+		if _, ok := d.keysByValue[res.Value()]; !ok {
+			d.keysByValue[res.Value()] = key
+		}
+
+		graph := d.rankSubgraph(d.clusterFor(resc, res, clusters, len(resc.Resources)), res)
+		node := graph.Node(key).Label(res.Value())
+
+		// Resources drawn as the same node keep the ID of the first one.
+		if d.config.ResourceIDs && node.Value(ResourceIDAttr) == nil {
+			node = node.Attr(ResourceIDAttr, res.ID())
+		}
+
+		if image, ok := d.resourceImage(res.ResourceType()); ok {
+			node = node.Attr("image", image)
 		}
 
+		node = d.applyResourceAttrs(node, res)
+		node = applyNodeRules(node, res, nodeRules)
+		node = d.applyDiffNodeStyle(node, res)
+
 		if color, ok := style.Nodes[res]; ok {
 			node = node.Attr("fontcolor", color)
 		}
 
-		nodes[res.Value()] = node
+		nodes[key] = node
 	}
 
-	for k, v := range style.Nodes {
-		nodes[k.Value()] = d.g.Node(k.Value()).Attr("fontcolor", v)
+	for _, k := range sortedResources(style.Nodes) {
+		v := style.Nodes[k]
+
+		if resc.IsContainer(k) {
+			continue
+		}
+
+		key := d.nodeKey(k)
+
+		node, ok := nodes[key]
+		if !ok {
+			d.errs = append(d.errs, fmt.Errorf("%w: node style of %s", ErrUnknownStyleReference, k.ID()))
 
-		if resourceImageMap != nil {
-			if image, ok := resourceImageMap[k.ResourceType()]; ok {
-				nodes[k.Value()] = nodes[k.Value()].Attr("image", image)
+			node = d.g.Node(key).Label(k.Value())
+			if d.config.ResourceIDs {
+				node = node.Attr(ResourceIDAttr, k.ID())
 			}
+
+			if _, ok := d.keysByValue[k.Value()]; !ok {
+				d.keysByValue[k.Value()] = key
+			}
+		}
+
+		nodes[key] = node.Attr("fontcolor", v)
+
+		if image, ok := d.resourceImage(k.ResourceType()); ok {
+			nodes[key] = nodes[key].Attr("image", image)
+		}
+	}
+}
+
+// resourceImage returns the image of the resource type from the ResourceImageMap of the Config or of its Theme.
+func (d *DotDiagram) resourceImage(resourceType string) (string, bool) {
+	if image, ok := d.config.ResourceImageMap[resourceType]; ok {
+		return image, true
+	}
+
+	if d.config.Theme != nil {
+		image, ok := d.config.Theme.ResourceImageMap[resourceType]
+		return image, ok
+	}
+
+	return "", false
+}
+
+// applyThemeClusterAttrs sets the cluster attributes of the Theme.
+func (d *DotDiagram) applyThemeClusterAttrs(cluster *dot.Graph) {
+	if d.config.Theme == nil {
+		return
+	}
+
+	for _, k := range sortedKeys(d.config.Theme.ClusterAttrs) {
+		cluster.Attr(k, d.config.Theme.ClusterAttrs[k])
+	}
+}
+
+// clusterFor returns the graph where the node of the resource must be created: the root graph for top-level resources
+// or the nested "cluster_*" subgraphs of its containers. The depth is limited to stop on cyclic hierarchies.
+func (d *DotDiagram) clusterFor(
+	resc *resources.ResourceCollection, res resources.Resource, clusters map[string]*dot.Graph, depth int,
+) *dot.Graph {
+	parent := resc.Parent(res)
+	if parent == nil || depth == 0 {
+		return d.groupCluster(res, clusters)
+	}
+
+	if cluster, ok := clusters[parent.ID()]; ok {
+		return cluster
+	}
+
+	cluster := d.clusterFor(resc, parent, clusters, depth-1).Subgraph(parent.ID(), dot.ClusterOption{})
+	cluster.Label(parent.Value())
+
+	if d.config.ResourceIDs {
+		cluster.Attr(ResourceIDAttr, parent.ID())
+	}
+
+	d.applyThemeClusterAttrs(cluster)
+	d.applyDiffClusterStyle(cluster, parent)
+
+	clusters[parent.ID()] = cluster
+
+	return cluster
+}
+
+// groupCluster returns the cluster of the group of a top-level resource, or the root graph when the resource has no
+// group.
+func (d *DotDiagram) groupCluster(res resources.Resource, clusters map[string]*dot.Graph) *dot.Graph {
+	if d.config.Cluster == nil {
+		return d.g
+	}
+
+	group := d.config.Cluster(res)
+	if group == "" {
+		return d.g
+	}
+
+	if cluster, ok := clusters[clusterKeyPrefix+group]; ok {
+		return cluster
+	}
+
+	clusterStyle, ok := d.config.ClusterStyles[group]
+	if !ok || clusterStyle.Label == "" {
+		clusterStyle.Label = group
+	}
+
+	cluster := d.g.Subgraph(clusterKeyPrefix+group, dot.ClusterOption{})
+	cluster.Label(clusterStyle.Label)
+	d.applyThemeClusterAttrs(cluster)
+
+	for _, k := range sortedKeys(clusterStyle.Attrs) {
+		cluster.Attr(k, clusterStyle.Attrs[k])
+	}
+
+	clusters[clusterKeyPrefix+group] = cluster
+
+	return cluster
+}
+
+// edgeEndpoint returns the node used to draw the edges of the resource. For a container, it is the node of its first
+// descendant together with the cluster ID, so the edge can be clipped at the cluster border with lhead or ltail.
+func (d *DotDiagram) edgeEndpoint(
+	resc *resources.ResourceCollection, res resources.Resource, nodes map[string]dot.Node,
+	clusters map[string]*dot.Graph,
+) (node dot.Node, clusterID string, ok bool) {
+	cluster, isCluster := clusters[res.ID()]
+	if !isCluster {
+		return nodes[d.nodeKey(res)], "", true
+	}
+
+	current := res
+
+	for depth := 0; depth < len(resc.Resources); depth++ {
+		children := resc.Children(current)
+		if len(children) == 0 {
+			break
+		}
+
+		current = children[0]
+
+		if node, ok = nodes[d.nodeKey(current)]; ok {
+			return node, cluster.GetID(), true
 		}
 	}
+
+	return dot.Node{}, "", false
+}
+
+// applyResourceAttrs sets the node attributes mapped from the resource attributes by ResourceAttrMap.
+func (d *DotDiagram) applyResourceAttrs(node dot.Node, res resources.Resource) dot.Node {
+	if len(d.config.ResourceAttrMap) == 0 {
+		return node
+	}
+
+	attributes := resources.AttributesOf(res)
+
+	for _, k := range sortedKeys(d.config.ResourceAttrMap) {
+		if value, ok := attributes[k]; ok {
+			node = node.Attr(d.config.ResourceAttrMap[k], value)
+		}
+	}
+
+	return node
 }
 
 func (d *DotDiagram) applyStyleForArrows(
 	resc *resources.ResourceCollection, edges map[string]struct{}, nodes map[string]dot.Node,
+	clusters map[string]*dot.Graph,
 ) {
 	style := d.config.Style
 	if style == nil {
 		style = &Style{}
 	}
 
-	for _, rel := range resc.Relationships {
+	ids := make(map[string]struct{}, len(resc.Resources))
+	for _, res := range resc.Resources {
+		ids[res.ID()] = struct{}{}
+	}
+
+	edgeRules := sortEdgeRules(style.EdgeRules)
+
+	for i, rel := range resc.Relationships {
 		if rel.Source == nil || rel.Target == nil {
+			d.errs = append(d.errs, fmt.Errorf("relationship %d: %w", i, resources.ErrNilEndpoint))
+			continue
+		}
+
+		if !d.hasEndpoints(i, rel, ids) {
 			continue
 		}
 
-		edgeKey := rel.Source.Value() + "###" + rel.Target.Value()
+		// Relationships with different kinds or labels between the same nodes are drawn as different edges.
+		pairKey := d.nodeKey(rel.Source) + "###" + d.nodeKey(rel.Target)
+		edgeKey := pairKey + "###" + rel.Kind + "###" + rel.Label
+
 		if _, ok := edges[edgeKey]; ok {
 			continue
 		}
 
-		sourceNode := nodes[rel.Source.Value()]
-		targetNode := nodes[rel.Target.Value()]
+		sourceNode, sourceCluster, okSource := d.edgeEndpoint(resc, rel.Source, nodes, clusters)
+		targetNode, targetCluster, okTarget := d.edgeEndpoint(resc, rel.Target, nodes, clusters)
+
+		if !okSource || !okTarget {
+			continue
+		}
+
+		edge := d.g.Edge(sourceNode, targetNode)
+
+		if sourceCluster != "" {
+			edge = edge.Attr("ltail", sourceCluster)
+		}
 
-		if color, ok := getArrowColor(style, rel); ok {
-			d.g.Edge(sourceNode, targetNode).Attr("color", color)
-		} else {
-			d.g.Edge(sourceNode, targetNode)
+		if targetCluster != "" {
+			edge = edge.Attr("lhead", targetCluster)
+		}
+
+		if sourceCluster != "" || targetCluster != "" {
+			d.g.Attr("compound", "true")
+		}
+
+		edge = d.applyRelationshipAttrs(edge, rel)
+		edge = applyEdgeRules(edge, rel, edgeRules)
+		edge = d.applyDiffEdgeStyle(edge, rel)
+
+		if color, ok := d.getArrowColor(style, rel); ok {
+			edge.Attr("color", color)
 		}
 
 		edges[edgeKey] = struct{}{}
+		edges[pairKey] = struct{}{}
 	}
 
 	d.applyCustomArrowStyles(style, edges, nodes)
 }
 
+// hasEndpoints reports whether both endpoints of the relationship are in the collection, recording an error for each
+// one that is not.
+func (d *DotDiagram) hasEndpoints(i int, rel resources.Relationship, ids map[string]struct{}) bool {
+	ok := true
+
+	for _, endpoint := range []resources.Resource{rel.Source, rel.Target} {
+		if _, found := ids[endpoint.ID()]; !found {
+			d.errs = append(d.errs, fmt.Errorf("relationship %d: %w: %s (%s)",
+				i, resources.ErrDanglingEndpoint, endpoint.ID(), endpoint.Value()))
+			ok = false
+		}
+	}
+
+	return ok
+}
+
+// applyRelationshipAttrs sets the edge attributes of the relationship kind, the ones mapped from the relationship
+// attributes by RelationshipAttrMap, the relationship label and the EdgeStyle options, in this order.
+func (d *DotDiagram) applyRelationshipAttrs(edge dot.Edge, rel resources.Relationship) dot.Edge {
+	kindAttrs := d.config.EdgeKindAttrs[rel.Kind]
+	for _, k := range sortedKeys(kindAttrs) {
+		edge = edge.Attr(k, kindAttrs[k])
+	}
+
+	for _, k := range sortedKeys(d.config.RelationshipAttrMap) {
+		if value, ok := rel.Attributes[k]; ok {
+			edge = edge.Attr(d.config.RelationshipAttrMap[k], value)
+		}
+	}
+
+	if rel.Label != "" {
+		edge = edge.Label(rel.Label)
+	}
+
+	if d.config.EdgeStyle != nil {
+		edge = d.config.EdgeStyle(rel).apply(edge)
+	}
+
+	return edge
+}
+
 func (d *DotDiagram) applyCustomArrowStyles(style *Style, edges map[string]struct{}, nodes map[string]dot.Node) {
-	for source, targets := range style.Arrows {
+	for _, source := range sortedKeys(style.Arrows) {
+		targets := style.Arrows[source]
+
 		for i := range targets {
-			for target, color := range targets[i] {
-				edgeKey := source + "###" + target
+			for _, target := range sortedKeys(targets[i]) {
+				color := targets[i][target]
 
-				if _, ok := edges[edgeKey]; !ok {
-					d.g.Edge(nodes[source], nodes[target]).Attr("color", color)
+				sourceKey, okSource := d.resolveNodeKey(source, nodes)
+				targetKey, okTarget := d.resolveNodeKey(target, nodes)
 
-					edges[edgeKey] = struct{}{}
+				if !okSource || !okTarget {
+					d.errs = append(d.errs,
+						fmt.Errorf("%w: arrow from %q to %q", ErrUnknownStyleReference, source, target))
+					continue
 				}
+
+				edgeKey := sourceKey + "###" + targetKey
+
+				if _, ok := edges[edgeKey]; ok {
+					continue
+				}
+
+				sourceNode, targetNode := nodes[sourceKey], nodes[targetKey]
+
+				d.g.Edge(sourceNode, targetNode).Attr("color", color)
+
+				edges[edgeKey] = struct{}{}
 			}
 		}
 	}
 }
 
+// elementAttrs returns the attributes of the nodes or edges: the configured ones, which replace the defaults, over the
+// ones of the theme.
+func elementAttrs(defaults, configured, themed map[string]any) map[string]any {
+	if len(configured) > 0 {
+		return mergeAnyAttrs(themed, configured)
+	}
+
+	return mergeAnyAttrs(defaults, themed)
+}
+
 func defaultConfig() *Config {
 	return &Config{
 		Direction: DefaultDirection,
@@ -177,14 +544,69 @@ func defaultConfig() *Config {
 	}
 }
 
-func getArrowColor(style *Style, rel resources.Relationship) (string, bool) {
-	if list, exists := style.Arrows[rel.Source.Value()]; exists {
-		for _, colors := range list {
-			if color, ok := colors[rel.Target.Value()]; ok {
-				return color, true
+// getArrowColor returns the Style.Arrows color of the relationship. The endpoints are named by their node key or by
+// their value.
+func (d *DotDiagram) getArrowColor(style *Style, rel resources.Relationship) (string, bool) {
+	for _, source := range []string{d.nodeKey(rel.Source), rel.Source.Value()} {
+		for _, colors := range style.Arrows[source] {
+			for _, target := range []string{d.nodeKey(rel.Target), rel.Target.Value()} {
+				if color, ok := colors[target]; ok {
+					return color, true
+				}
 			}
 		}
 	}
 
 	return "", false
 }
+
+// nodeKey returns the key of the node of the resource.
+func (d *DotDiagram) nodeKey(res resources.Resource) string {
+	if d.config.NodeKey != nil {
+		return d.config.NodeKey(res)
+	}
+
+	return NodeKeyByID(res)
+}
+
+// resolveNodeKey returns the key of the node named by a Style.Arrows entry, either its node key or the value of its
+// resource.
+func (d *DotDiagram) resolveNodeKey(name string, nodes map[string]dot.Node) (string, bool) {
+	if _, ok := nodes[name]; ok {
+		return name, true
+	}
+
+	key, ok := d.keysByValue[name]
+
+	return key, ok
+}
+
+// sortedResources returns the keys of a map of resources sorted by value and ID, so the map can be iterated in a
+// stable order.
+func sortedResources[V any](m map[resources.Resource]V) []resources.Resource {
+	keys := make([]resources.Resource, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Value() != keys[j].Value() {
+			return keys[i].Value() < keys[j].Value()
+		}
+
+		return keys[i].ID() < keys[j].ID()
+	})
+
+	return keys
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
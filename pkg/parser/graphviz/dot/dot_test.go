@@ -2,6 +2,7 @@ package dot
 
 import (
 	_ "embed"
+	"strings"
 	"testing"
 
 	"github.com/diagram-code-generator/resources/pkg/resources"
@@ -20,6 +21,37 @@ var (
 	lrOrientation []byte
 	//go:embed testdata/source_or_target_nil.dot
 	sourceOrTargetNil []byte
+	//go:embed testdata/resource_attrs.dot
+	resourceAttrs []byte
+	//go:embed testdata/relationship_labels.dot
+	relationshipLabels []byte
+
+	//go:embed testdata/containers.dot
+	containersDot []byte
+
+	//go:embed testdata/deterministic.dot
+	deterministicDot []byte
+
+	//go:embed testdata/clusters_by_type.dot
+	clustersByTypeDot []byte
+
+	//go:embed testdata/clusters_by_func.dot
+	clustersByFuncDot []byte
+
+	//go:embed testdata/style_rules.dot
+	styleRulesDot []byte
+
+	//go:embed testdata/edge_styles.dot
+	edgeStylesDot []byte
+
+	//go:embed testdata/theme_legend.dot
+	themeLegendDot []byte
+
+	//go:embed testdata/diff.dot
+	diffDot []byte
+
+	//go:embed testdata/graph_attrs_ranks.dot
+	graphAttrsRanksDot []byte
 )
 
 var (
@@ -40,6 +72,8 @@ func TestBuild(t *testing.T) {
 	sqsResource := resources.NewGenericResource("2", "my-queue", "sqs")
 	kinesisResource := resources.NewGenericResource("3", "MyStream", "kinesis")
 	databaseResource := resources.NewGenericResource("4", "doc", "database")
+	vpcResource := resources.NewGenericResource("5", "my-vpc", "vpc")
+	subnetResource := resources.NewGenericResource("6", "private", "subnet")
 
 	reourceImageMap := map[string]string{
 		"lambda": "images/lambda.svg",
@@ -228,6 +262,261 @@ func TestBuild(t *testing.T) {
 			},
 			want: string(sourceOrTargetNil),
 		},
+		{
+			name: "resource attributes mapped to node attributes",
+			fields: fields{
+				config: &Config{
+					ResourceImageMap: reourceImageMap,
+					ResourceAttrMap:  map[string]string{"description": "tooltip", "docs": "URL"},
+				},
+			},
+			args: args{
+				resc: &resources.ResourceCollection{
+					Resources: []resources.Resource{
+						resources.NewGenericResourceWithAttributes("1", "MyLambda", "lambda", map[string]string{
+							"description": "Receives the orders",
+							"docs":        "https://example.com/lambda",
+							"runtime":     "go",
+						}),
+						sqsResource,
+					},
+				},
+			},
+			want: string(resourceAttrs),
+		},
+		{
+			name: "labeled and typed relationships",
+			fields: fields{
+				config: &Config{
+					ResourceImageMap:    reourceImageMap,
+					RelationshipAttrMap: map[string]string{"description": "tooltip"},
+					EdgeKindAttrs:       map[string]map[string]any{"write": {"style": "dashed", "color": "blue"}},
+				},
+			},
+			args: args{
+				resc: &resources.ResourceCollection{
+					Resources: []resources.Resource{lambdaResource, sqsResource},
+					Relationships: []resources.Relationship{
+						{Source: lambdaResource, Target: sqsResource, Label: "reads from", Kind: "read"},
+						{
+							Source: lambdaResource, Target: sqsResource, Label: "writes to", Kind: "write",
+							Attributes: map[string]string{"description": "Sends the orders"},
+						},
+						{Source: lambdaResource, Target: sqsResource, Label: "writes to", Kind: "write"},
+					},
+				},
+			},
+			want: string(relationshipLabels),
+		},
+		{
+			name: "edge styles by relationship kind",
+			fields: fields{
+				config: &Config{
+					ResourceImageMap: reourceImageMap,
+					EdgeStyle: EdgeStyleByKind(map[string]EdgeStyle{
+						"async": {
+							Style: "dashed", ArrowHead: "empty", HeadLabel: "queue", TailLabel: "publish",
+							Constraint: func(b bool) *bool { return &b }(false),
+						},
+						"sync": {
+							Label: "calls", ArrowHead: "normal", TailPort: "e", HeadPort: "w",
+							Attrs: map[string]any{"penwidth": 2},
+						},
+					}),
+				},
+			},
+			args: args{
+				resc: &resources.ResourceCollection{
+					Resources: []resources.Resource{lambdaResource, sqsResource, kinesisResource},
+					Relationships: []resources.Relationship{
+						{Source: lambdaResource, Target: sqsResource, Label: "sends to", Kind: "async"},
+						{Source: lambdaResource, Target: kinesisResource, Label: "invokes", Kind: "sync"},
+						{Source: sqsResource, Target: kinesisResource},
+					},
+				},
+			},
+			want: string(edgeStylesDot),
+		},
+		{
+			name: "containers are clusters",
+			fields: fields{
+				config: &Config{ResourceImageMap: reourceImageMap},
+			},
+			args: args{
+				resc: &resources.ResourceCollection{
+					Resources: []resources.Resource{
+						vpcResource, subnetResource, lambdaResource, sqsResource, kinesisResource,
+					},
+					Relationships: []resources.Relationship{
+						{Source: lambdaResource, Target: sqsResource},
+						{Source: kinesisResource, Target: vpcResource},
+					},
+					Parents: map[string]string{
+						subnetResource.ID(): vpcResource.ID(),
+						lambdaResource.ID(): subnetResource.ID(),
+						sqsResource.ID():    vpcResource.ID(),
+					},
+				},
+			},
+			want: string(containersDot),
+		},
+		{
+			name: "clusters by resource type",
+			fields: fields{
+				config: &Config{
+					ResourceImageMap: reourceImageMap,
+					Cluster:          ClusterByType,
+					ClusterStyles: map[string]ClusterStyle{
+						"sqs": {Label: "Queues", Attrs: map[string]any{"style": "filled", "fillcolor": "#fff3e0"}},
+					},
+				},
+			},
+			args: args{
+				resc: &resources.ResourceCollection{
+					Resources: []resources.Resource{
+						lambdaResource, sqsResource, resources.NewGenericResource("7", "other-queue", "sqs"),
+					},
+					Relationships: []resources.Relationship{{Source: lambdaResource, Target: sqsResource}},
+				},
+			},
+			want: string(clustersByTypeDot),
+		},
+		{
+			name: "clusters by grouping function",
+			fields: fields{
+				config: &Config{
+					Cluster: func(res resources.Resource) string {
+						return resources.AttributesOf(res)["service"]
+					},
+					ClusterStyles: map[string]ClusterStyle{"orders": {Attrs: map[string]any{"color": "blue"}}},
+				},
+			},
+			args: args{
+				resc: &resources.ResourceCollection{
+					Resources: []resources.Resource{
+						resources.NewGenericResourceWithAttributes("1", "MyLambda", "lambda",
+							map[string]string{"service": "orders"}),
+						resources.NewGenericResourceWithAttributes("2", "my-queue", "sqs",
+							map[string]string{"service": "orders"}),
+						resources.NewGenericResourceWithAttributes("3", "MyStream", "kinesis",
+							map[string]string{"service": "billing"}),
+						databaseResource,
+					},
+				},
+			},
+			want: string(clustersByFuncDot),
+		},
+		{
+			name: "graph attributes and ranks",
+			fields: fields{
+				config: &Config{
+					ResourceImageMap: reourceImageMap,
+					NodeSep:          0.5,
+					RankSep:          1.2,
+					Concentrate:      true,
+					NewRank:          true,
+					Label:            "Orders",
+					LabelLoc:         "t",
+					BgColor:          "white",
+					FontName:         "Arial",
+					FontSize:         18,
+					Size:             "8,6",
+					Ratio:            "fill",
+					GraphAttrs:       map[string]any{"pad": 0.2, "bgcolor": "black"},
+					Ranks: []RankConstraint{
+						{Rank: RankSource, Types: []string{"apigateway"}},
+						{Rank: RankSink, Types: []string{"sqs"}, Resources: []resources.Resource{kinesisResource}},
+					},
+				},
+			},
+			args: args{
+				resc: &resources.ResourceCollection{
+					Resources: []resources.Resource{
+						resources.NewGenericResource("4", "api", "apigateway"), lambdaResource, sqsResource,
+						kinesisResource,
+					},
+					Relationships: []resources.Relationship{
+						{Source: lambdaResource, Target: sqsResource},
+						{Source: lambdaResource, Target: kinesisResource},
+					},
+				},
+			},
+			want: string(graphAttrsRanksDot),
+		},
+		{
+			name: "theme and legend",
+			fields: fields{
+				config: &Config{
+					ResourceImageMap: map[string]string{"lambda": "images/lambda.svg"},
+					NodeAttrs:        map[string]any{"shape": "plaintext", "fontcolor": "white"},
+					Theme: &Theme{
+						GraphAttrs:       map[string]any{"bgcolor": "black"},
+						NodeAttrs:        map[string]any{"fontcolor": "gray", "fontname": "Helvetica"},
+						EdgeAttrs:        map[string]any{"color": "white"},
+						ClusterAttrs:     map[string]any{"style": "rounded"},
+						ResourceImageMap: map[string]string{"lambda": "images/other.svg", "sqs": "images/sqs.svg"},
+					},
+					Legend: &Legend{
+						Attrs: map[string]any{"style": "dashed"},
+						Edges: []LegendEdge{
+							{Description: "async call", Style: EdgeStyle{Style: "dashed"}},
+							{Description: "sync call"},
+						},
+					},
+					EdgeStyle: EdgeStyleByKind(map[string]EdgeStyle{"async": {Style: "dashed"}}),
+				},
+			},
+			args: args{
+				resc: &resources.ResourceCollection{
+					Resources: []resources.Resource{lambdaResource, sqsResource},
+					Relationships: []resources.Relationship{
+						{Source: lambdaResource, Target: sqsResource, Kind: "async"},
+					},
+				},
+			},
+			want: string(themeLegendDot),
+		},
+		{
+			name: "style rules",
+			fields: fields{
+				config: &Config{
+					ResourceImageMap: reourceImageMap,
+					Style: &Style{
+						Nodes: map[resources.Resource]string{lambdaResource: "green"},
+						NodeRules: []NodeRule{
+							// The most specific rule wins regardless of the order.
+							{Match: Match{Value: "my-*"}, Attrs: map[string]any{"fillcolor": "orange"}},
+							{Match: Match{Type: "sqs"}, Attrs: map[string]any{
+								"shape": "box", "style": "filled", "fillcolor": "yellow",
+							}},
+							{Match: Match{}, Attrs: map[string]any{"fontcolor": "black"}},
+							{Match: Match{Type: "kinesis"}, Attrs: map[string]any{"URL": "https://example.com/stream"}},
+							{Match: Match{Attributes: map[string]string{"team": "data"}}, Attrs: map[string]any{
+								"tooltip": "Owned by the data team",
+							}},
+						},
+						EdgeRules: []EdgeRule{
+							{Kind: "async", Attrs: map[string]any{"style": "dashed"}},
+							{Target: Match{Type: "kinesis"}, Attrs: map[string]any{"penwidth": 2, "style": "bold"}},
+						},
+					},
+				},
+			},
+			args: args{
+				resc: &resources.ResourceCollection{
+					Resources: []resources.Resource{
+						lambdaResource, sqsResource,
+						resources.NewGenericResourceWithAttributes("3", "MyStream", "kinesis",
+							map[string]string{"team": "data"}),
+					},
+					Relationships: []resources.Relationship{
+						{Source: lambdaResource, Target: sqsResource},
+						{Source: lambdaResource, Target: kinesisResource, Kind: "async"},
+					},
+				},
+			},
+			want: string(styleRulesDot),
+		},
 		{
 			name: "default config",
 			fields: fields{
@@ -256,3 +545,295 @@ func TestBuild(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildE(t *testing.T) {
+	lambdaResource := resources.NewGenericResource("1", "MyLambda", "lambda")
+	sqsResource := resources.NewGenericResource("2", "my-queue", "sqs")
+	kinesisResource := resources.NewGenericResource("3", "MyStream", "kinesis")
+
+	validCollection := &resources.ResourceCollection{
+		Resources:     []resources.Resource{lambdaResource, sqsResource},
+		Relationships: []resources.Relationship{{Source: lambdaResource, Target: sqsResource}},
+	}
+
+	tests := []struct {
+		name       string
+		config     *Config
+		resc       *resources.ResourceCollection
+		targetErrs []error
+	}{
+		{
+			name: "valid collection",
+			resc: validCollection,
+		},
+		{
+			name: "unknown style references",
+			config: &Config{Style: &Style{Arrows: map[string][]map[string]string{
+				"MyLambda": {{"unknown": "red"}},
+				"unknown":  {{"my-queue": "red"}},
+			}}},
+			resc:       validCollection,
+			targetErrs: []error{ErrUnknownStyleReference},
+		},
+		{
+			name: "node style of a resource that is not in the collection",
+			config: &Config{Style: &Style{Nodes: map[resources.Resource]string{
+				kinesisResource: "red",
+			}}},
+			resc:       validCollection,
+			targetErrs: []error{ErrUnknownStyleReference},
+		},
+		{
+			name: "duplicate node keys",
+			resc: &resources.ResourceCollection{
+				Resources: []resources.Resource{
+					lambdaResource, resources.NewGenericResource("1", "OtherLambda", "lambda"),
+				},
+			},
+			targetErrs: []error{ErrDuplicateNodeKey},
+		},
+		{
+			name: "invalid relationships",
+			resc: &resources.ResourceCollection{
+				Resources: []resources.Resource{lambdaResource, sqsResource},
+				Relationships: []resources.Relationship{
+					{Source: lambdaResource, Target: kinesisResource},
+					{Source: nil, Target: sqsResource},
+				},
+			},
+			targetErrs: []error{resources.ErrDanglingEndpoint, resources.ErrNilEndpoint},
+		},
+	}
+
+	for i := range tests {
+		tc := tests[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := NewDotDiagram(tc.config).BuildE(tc.resc)
+
+			if len(tc.targetErrs) == 0 {
+				require.NoError(t, err)
+				require.Equal(t, NewDotDiagram(tc.config).Build(tc.resc), got)
+
+				return
+			}
+
+			require.Empty(t, got)
+
+			for _, targetErr := range tc.targetErrs {
+				require.ErrorIs(t, err, targetErr)
+			}
+		})
+	}
+}
+
+func TestBuild_SkipsUnknownStyleReferences(t *testing.T) {
+	lambdaResource := resources.NewGenericResource("1", "MyLambda", "lambda")
+	sqsResource := resources.NewGenericResource("2", "my-queue", "sqs")
+
+	resc := &resources.ResourceCollection{Resources: []resources.Resource{lambdaResource, sqsResource}}
+
+	got := NewDotDiagram(&Config{Style: &Style{Arrows: map[string][]map[string]string{
+		"MyLambda": {{"unknown": "red"}},
+	}}}).Build(resc)
+
+	require.Equal(t, NewDotDiagram(&Config{}).Build(resc), got)
+}
+
+func TestBuild_Deterministic(t *testing.T) {
+	lambdaResource := resources.NewGenericResource("1", "MyLambda", "lambda")
+	sqsResource := resources.NewGenericResource("2", "my-queue", "sqs")
+	kinesisResource := resources.NewGenericResource("3", "MyStream", "kinesis")
+	databaseResource := resources.NewGenericResource("4", "doc", "database")
+	bucketResource := resources.NewGenericResource("5", "my-bucket", "s3")
+	topicResource := resources.NewGenericResource("6", "my-topic", "sns")
+
+	config := &Config{
+		NodeAttrs: map[string]any{"shape": "box", "style": "rounded", "fontname": "Arial", "fontsize": 10},
+		EdgeAttrs: map[string]any{"arrowhead": "vee", "penwidth": 2, "fontname": "Arial"},
+		Style: &Style{
+			// Resources that are not in the collection are drawn as extra nodes.
+			Nodes: map[resources.Resource]string{
+				lambdaResource:   "green",
+				bucketResource:   "blue",
+				topicResource:    "orange",
+				databaseResource: "red",
+			},
+			Arrows: map[string][]map[string]string{
+				"my-topic":  {{"MyLambda": "orange", "my-queue": "orange"}},
+				"my-bucket": {{"MyLambda": "blue"}, {"doc": "blue"}},
+				"MyLambda":  {{"my-queue": "red", "MyStream": "green", "doc": "gray"}},
+			},
+		},
+	}
+
+	resc := &resources.ResourceCollection{
+		Resources: []resources.Resource{lambdaResource, sqsResource, kinesisResource, databaseResource},
+		Relationships: []resources.Relationship{
+			{Source: lambdaResource, Target: sqsResource},
+			{Source: kinesisResource, Target: lambdaResource},
+		},
+	}
+
+	for i := 0; i < 50; i++ {
+		require.Equal(t, string(deterministicDot), NewDotDiagram(config).Build(resc))
+	}
+}
+
+func TestDotDiagram_BuildDiff(t *testing.T) {
+	vpc := resources.NewGenericResource("10", "my-vpc", "vpc")
+	oldVPC := resources.NewGenericResource("11", "old-vpc", "vpc")
+	lambda1 := resources.NewGenericResource("1", "MyLambda", "lambda")
+	lambda2 := resources.NewGenericResourceWithAttributes("1", "MyLambda", "lambda", map[string]string{"runtime": "go"})
+	queue := resources.NewGenericResource("2", "my-queue", "sqs")
+	stream := resources.NewGenericResource("3", "MyStream", "kinesis")
+	topic := resources.NewGenericResource("4", "my-topic", "sns")
+
+	before := &resources.ResourceCollection{
+		Resources: []resources.Resource{vpc, oldVPC, lambda1, queue, stream},
+		Relationships: []resources.Relationship{
+			{Source: lambda1, Target: queue, Label: "writes to"},
+			{Source: lambda1, Target: stream, Label: "puts <records>"},
+		},
+		Parents: map[string]string{"1": "10", "3": "11"},
+	}
+
+	after := &resources.ResourceCollection{
+		Resources: []resources.Resource{vpc, lambda2, queue, topic},
+		Relationships: []resources.Relationship{
+			{Source: lambda2, Target: queue, Label: "sends to"},
+			{Source: lambda2, Target: topic},
+		},
+		Parents: map[string]string{"1": "10"},
+	}
+
+	d := NewDotDiagram(&Config{ResourceImageMap: map[string]string{"lambda": "images/lambda.svg"}})
+
+	require.Equal(t, string(diffDot), d.BuildDiff(before, after))
+
+	// The statuses are only drawn by BuildDiff.
+	text := NewDotDiagram(nil).Build(resources.DiffCollection(before, after))
+
+	require.NotContains(t, text, DiffColors[resources.DiffAdded])
+}
+
+func TestDotDiagram_BuildDiff_NilEndpoint(t *testing.T) {
+	lambda := resources.NewGenericResource("1", "MyLambda", "lambda")
+	queue := resources.NewGenericResource("2", "my-queue", "sqs")
+
+	resc := &resources.ResourceCollection{
+		Resources:     []resources.Resource{lambda, queue},
+		Relationships: []resources.Relationship{{Source: lambda}, {Source: lambda, Target: queue}},
+	}
+
+	text := NewDotDiagram(nil).BuildDiff(resc, resc)
+
+	require.Contains(t, text, "->")
+}
+
+func TestBuild_NodeKey(t *testing.T) {
+	lambda := resources.NewGenericResource("1", "MyLambda", "lambda")
+	queue := resources.NewGenericResource("2", "orders", "sqs")
+	table := resources.NewGenericResource("3", "orders", "dynamodb")
+
+	resc := &resources.ResourceCollection{
+		Resources: []resources.Resource{lambda, queue, table},
+		Relationships: []resources.Relationship{
+			{Source: lambda, Target: queue},
+			{Source: lambda, Target: table},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		nodeKey NodeKeyFunc
+		arrows  map[string][]map[string]string
+		want    string
+	}{
+		{
+			name: "resources with the same value are different nodes",
+			// Arrows name the nodes by key or, for the first node with that value, by value.
+			arrows: map[string][]map[string]string{"1": {{"3": "blue"}}, "MyLambda": {{"orders": "red"}}},
+			want: `digraph  {
+	
+	n1[label="MyLambda",shape="box"];
+	n2[label="orders",shape="box"];
+	n3[label="orders",shape="box"];
+	n1->n2[arrowhead="vee",arrowtail="normal",color="red"];
+	n1->n3[arrowhead="vee",arrowtail="normal",color="blue"];
+	
+}
+`,
+		},
+		{
+			name:    "resources with the same value are a single node",
+			nodeKey: NodeKeyByValue,
+			want: `digraph  {
+	
+	n1[label="MyLambda",shape="box"];
+	n2[label="orders",shape="box"];
+	n1->n2[arrowhead="vee",arrowtail="normal"];
+	
+}
+`,
+		},
+	}
+
+	for i := range tests {
+		tc := tests[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			config := &Config{
+				NodeKey:   tc.nodeKey,
+				NodeAttrs: map[string]any{"shape": "box"},
+				Style:     &Style{Arrows: tc.arrows},
+			}
+
+			require.Equal(t, tc.want, NewDotDiagram(config).Build(resc))
+		})
+	}
+}
+
+func TestBuild_ResourceIDs(t *testing.T) {
+	vpc := resources.NewGenericResource("10", "my-vpc", "vpc")
+	lambda := resources.NewGenericResource("1", "MyLambda", "lambda")
+	queue := resources.NewGenericResource("2", "orders", "sqs")
+	otherQueue := resources.NewGenericResource("3", "orders", "sqs")
+
+	resc := &resources.ResourceCollection{
+		Resources:     []resources.Resource{vpc, lambda, queue, otherQueue},
+		Relationships: []resources.Relationship{{Source: lambda, Target: queue}},
+		Parents:       map[string]string{"1": "10"},
+	}
+
+	config := &Config{NodeKey: NodeKeyByValue, NodeAttrs: map[string]any{"shape": "box"}, ResourceIDs: true}
+
+	require.Equal(t, `digraph  {
+	subgraph cluster_s1 {
+		id="10";label="my-vpc";
+		n2[id="1",label="MyLambda",shape="box"];
+		
+	}
+	
+	n3[id="2",label="orders",shape="box"];
+	n2->n3[arrowhead="vee",arrowtail="normal"];
+	
+}
+`, NewDotDiagram(config).Build(resc))
+
+	require.NotContains(t, NewDotDiagram(&Config{}).Build(resc), ResourceIDAttr+"=")
+}
+
+func TestBuild_LegendRepeatedDescriptions(t *testing.T) {
+	config := &Config{Legend: &Legend{Edges: []LegendEdge{
+		{Description: "call", Style: EdgeStyle{Style: "dashed"}},
+		{Description: "call", Style: EdgeStyle{Style: "dotted"}},
+	}}}
+
+	got := NewDotDiagram(config).Build(&resources.ResourceCollection{})
+
+	require.Equal(t, 4, strings.Count(got, `shape="point"`))
+	require.Contains(t, got, `n2->n3[arrowhead="vee",arrowtail="normal",label="call",style="dashed"];`)
+	require.Contains(t, got, `n3->n4[arrowhead="vee",arrowtail="normal",style="invis"];`)
+	require.Contains(t, got, `n4->n5[arrowhead="vee",arrowtail="normal",label="call",style="dotted"];`)
+}
@@ -0,0 +1,67 @@
+package dot
+
+import (
+	"strconv"
+
+	"github.com/emicklei/dot"
+
+	"github.com/diagram-code-generator/resources/pkg/resources"
+)
+
+// EdgeStyle holds the rendering options of the edge of a relationship. Empty fields are not set.
+type EdgeStyle struct {
+	// Label replaces the relationship label.
+	Label     string
+	HeadLabel string
+	TailLabel string
+	// Style is the line style, e.g. "dashed", "dotted" or "bold".
+	Style     string
+	ArrowHead string
+	ArrowTail string
+	// HeadPort and TailPort are the node ports the edge is attached to, as a compass point ("n", "se", ...) or a
+	// "port:compass" pair.
+	HeadPort string
+	TailPort string
+	// Constraint set to false keeps the edge from being used to rank the nodes.
+	Constraint *bool
+	// Attrs holds any other DOT edge attribute.
+	Attrs map[string]any
+}
+
+// EdgeStyleFunc returns the rendering options of a relationship, e.g. dashed edges for asynchronous calls.
+type EdgeStyleFunc func(rel resources.Relationship) EdgeStyle
+
+// EdgeStyleByKind returns an EdgeStyleFunc that styles the relationships by their kind.
+func EdgeStyleByKind(styles map[string]EdgeStyle) EdgeStyleFunc {
+	return func(rel resources.Relationship) EdgeStyle {
+		return styles[rel.Kind]
+	}
+}
+
+// apply sets the edge attributes of the style.
+func (s EdgeStyle) apply(edge dot.Edge) dot.Edge {
+	for _, attr := range []struct{ key, value string }{
+		{"label", s.Label},
+		{"headlabel", s.HeadLabel},
+		{"taillabel", s.TailLabel},
+		{"style", s.Style},
+		{"arrowhead", s.ArrowHead},
+		{"arrowtail", s.ArrowTail},
+		{"headport", s.HeadPort},
+		{"tailport", s.TailPort},
+	} {
+		if attr.value != "" {
+			edge = edge.Attr(attr.key, attr.value)
+		}
+	}
+
+	if s.Constraint != nil {
+		edge = edge.Attr("constraint", strconv.FormatBool(*s.Constraint))
+	}
+
+	for _, k := range sortedKeys(s.Attrs) {
+		edge = edge.Attr(k, s.Attrs[k])
+	}
+
+	return edge
+}
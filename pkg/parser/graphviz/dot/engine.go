@@ -0,0 +1,34 @@
+package dot
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Engine is a Graphviz layout engine. It is not part of the DOT output, but is shared by the packages that lay the
+// diagrams out, some of which need cgo.
+type Engine string
+
+// Layout engines: https://graphviz.org/docs/layouts.
+const (
+	EngineDot   Engine = "dot"
+	EngineNeato Engine = "neato"
+	EngineFDP   Engine = "fdp"
+	EngineCirco Engine = "circo"
+	EngineTwopi Engine = "twopi"
+)
+
+const DefaultEngine Engine = EngineDot
+
+var ErrUnsupportedEngine = errors.New("unsupported layout engine")
+
+// Validate returns ErrUnsupportedEngine when the engine is neither empty, which keeps DefaultEngine, nor one of the
+// layout engines.
+func (e Engine) Validate() error {
+	switch e {
+	case "", EngineDot, EngineNeato, EngineFDP, EngineCirco, EngineTwopi:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedEngine, e)
+	}
+}
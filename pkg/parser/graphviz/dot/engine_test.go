@@ -0,0 +1,29 @@
+package dot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		engine    Engine
+		targetErr error
+	}{
+		{name: "empty engine", engine: ""},
+		{name: "dot engine", engine: EngineDot},
+		{name: "neato engine", engine: EngineNeato},
+		{name: "fdp engine", engine: EngineFDP},
+		{name: "circo engine", engine: EngineCirco},
+		{name: "twopi engine", engine: EngineTwopi},
+		{name: "unsupported engine", engine: "unknown", targetErr: ErrUnsupportedEngine},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorIs(t, tt.engine.Validate(), tt.targetErr)
+		})
+	}
+}
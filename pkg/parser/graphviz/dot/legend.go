@@ -0,0 +1,109 @@
+package dot
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/emicklei/dot"
+
+	"github.com/diagram-code-generator/resources/pkg/resources"
+)
+
+const (
+	// DefaultLegendLabel is the label of a legend without one.
+	DefaultLegendLabel = "Legend"
+
+	legendKey = "legend"
+)
+
+// LegendAttr marks the legend cluster and its nodes, set to "true", so they can be told apart from the resources.
+const LegendAttr = "legend"
+
+// Legend describes the legend cluster of a diagram. It lists each resource type of the collection, with its image,
+// followed by each edge style with its meaning.
+type Legend struct {
+	Label string
+	Attrs map[string]any
+	Edges []LegendEdge
+}
+
+// LegendEdge is an edge style shown in the legend, e.g. {Description: "async call", Style: EdgeStyle{Style: "dashed"}}.
+type LegendEdge struct {
+	Description string
+	Style       EdgeStyle
+}
+
+// applyLegend adds the legend cluster to the diagram.
+func (d *DotDiagram) applyLegend(resc *resources.ResourceCollection) {
+	legend := d.config.Legend
+	if legend == nil {
+		return
+	}
+
+	label := legend.Label
+	if label == "" {
+		label = DefaultLegendLabel
+	}
+
+	cluster := d.g.Subgraph(legendKey, dot.ClusterOption{})
+	cluster.Label(label)
+	cluster.Attr(LegendAttr, "true")
+	d.applyThemeClusterAttrs(cluster)
+
+	for _, k := range sortedKeys(legend.Attrs) {
+		cluster.Attr(k, legend.Attrs[k])
+	}
+
+	for _, resourceType := range legendResourceTypes(resc) {
+		node := cluster.Node(legendKey+":type:"+resourceType).Label(resourceType).Attr(LegendAttr, "true")
+
+		if image, ok := d.resourceImage(resourceType); ok {
+			node.Attr("image", image)
+		}
+	}
+
+	// The edges are keyed by index, as descriptions may repeat.
+	for i, legendEdge := range legend.Edges {
+		key := legendEdgeKey(i)
+
+		from := cluster.Node(key+":from").Attr("shape", "point").Label("").Attr(LegendAttr, "true")
+		to := cluster.Node(key+":to").Attr("shape", "point").Label("").Attr(LegendAttr, "true")
+
+		style := legendEdge.Style
+		if style.Label == "" {
+			style.Label = legendEdge.Description
+		}
+
+		style.apply(cluster.Edge(from, to))
+
+		// Keeps the edges ordered from top to bottom.
+		if i > 0 {
+			cluster.Edge(cluster.Node(legendEdgeKey(i-1)+":to"), from).Attr("style", "invis")
+		}
+	}
+}
+
+func legendEdgeKey(i int) string {
+	return legendKey + ":edge:" + strconv.Itoa(i)
+}
+
+// legendResourceTypes returns the sorted types of the resources drawn as nodes.
+func legendResourceTypes(resc *resources.ResourceCollection) []string {
+	seen := map[string]struct{}{}
+	types := []string{}
+
+	for _, res := range resc.Resources {
+		if resc.IsContainer(res) {
+			continue
+		}
+
+		if _, ok := seen[res.ResourceType()]; !ok {
+			seen[res.ResourceType()] = struct{}{}
+			types = append(types, res.ResourceType())
+		}
+	}
+
+	sort.Strings(types)
+
+	return types
+}
@@ -0,0 +1,23 @@
+package dot
+
+import "github.com/diagram-code-generator/resources/pkg/resources"
+
+// ResourceIDAttr is the attribute holding the resource ID of the nodes and container clusters when Config.ResourceIDs
+// is set, as their DOT IDs are generated. It is the Graphviz "id" attribute, so the resource IDs are kept in the SVG
+// output too.
+const ResourceIDAttr = "id"
+
+// NodeKeyFunc returns the key identifying the node of a resource. Resources with the same key are drawn as a single
+// node.
+type NodeKeyFunc func(res resources.Resource) string
+
+// NodeKeyByID keys the nodes by resource ID, so resources with the same value are drawn as different nodes. It is the
+// default.
+func NodeKeyByID(res resources.Resource) string {
+	return res.ID()
+}
+
+// NodeKeyByValue keys the nodes by resource value, drawing resources with the same value as a single node.
+func NodeKeyByValue(res resources.Resource) string {
+	return res.Value()
+}
@@ -0,0 +1,64 @@
+package dot
+
+import (
+	"strconv"
+
+	"github.com/emicklei/dot"
+
+	"github.com/diagram-code-generator/resources/pkg/resources"
+)
+
+type Rank string
+
+// Rank constraints of the nodes of a subgraph: https://graphviz.org/docs/attrs/rank.
+const (
+	RankSame   Rank = "same"
+	RankMin    Rank = "min"
+	RankMax    Rank = "max"
+	RankSource Rank = "source"
+	RankSink   Rank = "sink"
+)
+
+// RankConstraint puts the nodes of the resources of the given types, or of the given resources, in the same rank
+// subgraph, e.g. ingress resources at the top with RankSource and storage resources at the bottom with RankSink.
+// Resources inside a cluster are ranked within that cluster.
+type RankConstraint struct {
+	Rank      Rank
+	Types     []string
+	Resources []resources.Resource
+}
+
+// matches reports whether the constraint applies to the resource.
+func (c RankConstraint) matches(res resources.Resource) bool {
+	for _, resourceType := range c.Types {
+		if resourceType == res.ResourceType() {
+			return true
+		}
+	}
+
+	for _, other := range c.Resources {
+		if other != nil && other.ID() == res.ID() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// rankSubgraph returns the rank subgraph of the first constraint matching the resource, inside the graph the node of
+// the resource belongs to, or that graph when no constraint matches.
+func (d *DotDiagram) rankSubgraph(g *dot.Graph, res resources.Resource) *dot.Graph {
+	for i, constraint := range d.config.Ranks {
+		if !constraint.matches(res) {
+			continue
+		}
+
+		sub := g.Subgraph("rank:" + strconv.Itoa(i))
+		sub.Delete("label")
+		sub.Attr("rank", string(constraint.Rank))
+
+		return sub
+	}
+
+	return g
+}
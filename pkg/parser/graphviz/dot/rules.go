@@ -0,0 +1,185 @@
+package dot
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/emicklei/dot"
+
+	"github.com/diagram-code-generator/resources/pkg/resources"
+)
+
+// Match selects resources. Every criterion that is set must match, and a Match without criteria matches every
+// resource.
+type Match struct {
+	// Type is the resource type.
+	Type string
+	// ID and Value are glob patterns, where "*" matches any sequence of characters, including "/", and "?" any single
+	// character, e.g. "orders-*".
+	ID    string
+	Value string
+	// Attributes holds the values the resource attributes must have.
+	Attributes map[string]string
+}
+
+// NodeRule sets the DOT attributes of the nodes of the resources it matches, e.g. shape, fillcolor, tooltip or URL.
+type NodeRule struct {
+	Match Match
+	Attrs map[string]any
+}
+
+// EdgeRule sets the DOT attributes of the edges of the relationships it matches, e.g. style=dashed or penwidth.
+type EdgeRule struct {
+	Source Match
+	Target Match
+	// Kind is the relationship kind and Label a glob pattern for the relationship label.
+	Kind  string
+	Label string
+	Attrs map[string]any
+}
+
+// Matches reports whether the resource matches every criterion.
+func (m Match) Matches(res resources.Resource) bool {
+	if m.Type != "" && m.Type != res.ResourceType() {
+		return false
+	}
+
+	if !globMatch(m.ID, res.ID()) || !globMatch(m.Value, res.Value()) {
+		return false
+	}
+
+	attributes := resources.AttributesOf(res)
+
+	for k, v := range m.Attributes {
+		if value, ok := attributes[k]; !ok || value != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// specificity ranks the criteria, so the attributes set by rules matching by ID override the ones set by rules matching
+// by value, then by attributes and finally by type. Patterns without literal characters, like "*", match everything,
+// so they don't count.
+func (m Match) specificity() int {
+	specificity := 0
+
+	for _, criterion := range []struct {
+		set    bool
+		weight int
+	}{
+		{hasLiterals(m.ID), 8}, {hasLiterals(m.Value), 4}, {len(m.Attributes) > 0, 2}, {m.Type != "", 1},
+	} {
+		if criterion.set {
+			specificity += criterion.weight
+		}
+	}
+
+	return specificity
+}
+
+// hasLiterals reports whether the pattern has characters other than wildcards.
+func hasLiterals(pattern string) bool {
+	return strings.Trim(pattern, "*?") != ""
+}
+
+// Matches reports whether the relationship matches every criterion.
+func (r EdgeRule) Matches(rel resources.Relationship) bool {
+	return (r.Kind == "" || r.Kind == rel.Kind) && globMatch(r.Label, rel.Label) &&
+		r.Source.Matches(rel.Source) && r.Target.Matches(rel.Target)
+}
+
+func (r EdgeRule) specificity() int {
+	specificity := r.Source.specificity() + r.Target.specificity()
+
+	if r.Kind != "" || hasLiterals(r.Label) {
+		// The relationship criteria are more specific than any combination of endpoint criteria.
+		specificity += 32
+	}
+
+	return specificity
+}
+
+// sortNodeRules returns a copy of the rules sorted from the least to the most specific one. Rules with the same
+// specificity keep their order, so the last one wins.
+func sortNodeRules(rules []NodeRule) []NodeRule {
+	ordered := make([]NodeRule, len(rules))
+	copy(ordered, rules)
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Match.specificity() < ordered[j].Match.specificity()
+	})
+
+	return ordered
+}
+
+// sortEdgeRules returns a copy of the rules sorted with the same precedence as sortNodeRules.
+func sortEdgeRules(rules []EdgeRule) []EdgeRule {
+	ordered := make([]EdgeRule, len(rules))
+	copy(ordered, rules)
+
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].specificity() < ordered[j].specificity() })
+
+	return ordered
+}
+
+// applyNodeRules sets the attributes of the matching rules, in order. The rules are sorted once per build by
+// sortNodeRules, instead of for every node.
+func applyNodeRules(node dot.Node, res resources.Resource, rules []NodeRule) dot.Node {
+	for _, rule := range rules {
+		if rule.Match.Matches(res) {
+			for _, k := range sortedKeys(rule.Attrs) {
+				node = node.Attr(k, rule.Attrs[k])
+			}
+		}
+	}
+
+	return node
+}
+
+// applyEdgeRules sets the attributes of the matching rules, in order. The rules are sorted once per build by
+// sortEdgeRules.
+func applyEdgeRules(edge dot.Edge, rel resources.Relationship, rules []EdgeRule) dot.Edge {
+	for _, rule := range rules {
+		if rule.Matches(rel) {
+			for _, k := range sortedKeys(rule.Attrs) {
+				edge = edge.Attr(k, rule.Attrs[k])
+			}
+		}
+	}
+
+	return edge
+}
+
+// globMatch reports whether the value matches the pattern, where "*" matches any sequence of characters and "?" any
+// single character. An empty pattern matches every value.
+func globMatch(pattern, value string) bool {
+	if pattern == "" {
+		return true
+	}
+
+	p, v := []rune(pattern), []rune(value)
+
+	// i and j are the positions in the pattern and in the value, and star and next the positions of the last "*" and
+	// of the value character it is retried from.
+	i, j, star, next := 0, 0, -1, 0
+
+	for j < len(v) {
+		switch {
+		case i < len(p) && (p[i] == '?' || p[i] == v[j]):
+			i++
+			j++
+		case i < len(p) && p[i] == '*':
+			star, next = i, j
+			i++
+		case star >= 0:
+			next++
+			i, j = star+1, next
+		default:
+			return false
+		}
+	}
+
+	return strings.Trim(string(p[i:]), "*") == ""
+}
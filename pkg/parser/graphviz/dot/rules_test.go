@@ -0,0 +1,122 @@
+package dot
+
+import (
+	"testing"
+
+	"github.com/emicklei/dot"
+	"github.com/stretchr/testify/require"
+
+	"github.com/diagram-code-generator/resources/pkg/resources"
+)
+
+func TestMatch_Matches(t *testing.T) {
+	queue := resources.NewGenericResourceWithAttributes("orders-queue", "orders", "sqs",
+		map[string]string{"team": "checkout"})
+
+	tests := []struct {
+		name  string
+		match Match
+		want  bool
+	}{
+		{name: "empty match", match: Match{}, want: true},
+		{name: "type", match: Match{Type: "sqs"}, want: true},
+		{name: "other type", match: Match{Type: "lambda"}, want: false},
+		{name: "ID glob", match: Match{ID: "orders-*"}, want: true},
+		{name: "value glob", match: Match{Value: "ord?rs"}, want: true},
+		{name: "other value", match: Match{Value: "billing*"}, want: false},
+		{name: "invalid glob", match: Match{Value: "["}, want: false},
+		{name: "attributes", match: Match{Attributes: map[string]string{"team": "checkout"}}, want: true},
+		{name: "other attribute value", match: Match{Attributes: map[string]string{"team": "billing"}}, want: false},
+		{name: "missing attribute", match: Match{Attributes: map[string]string{"owner": ""}}, want: false},
+		{name: "every criterion", match: Match{Type: "sqs", ID: "orders-*", Value: "orders"}, want: true},
+		{name: "one criterion fails", match: Match{Type: "sqs", ID: "billing-*"}, want: false},
+	}
+
+	for i := range tests {
+		tc := tests[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, tc.match.Matches(queue))
+		})
+	}
+}
+
+func TestEdgeRule_Matches(t *testing.T) {
+	lambda := resources.NewGenericResource("1", "MyLambda", "lambda")
+	queue := resources.NewGenericResource("2", "my-queue", "sqs")
+
+	rel := resources.Relationship{Source: lambda, Target: queue, Label: "writes to", Kind: "async"}
+
+	require.True(t, EdgeRule{}.Matches(rel))
+	require.True(t, EdgeRule{Kind: "async", Label: "writes*", Target: Match{Type: "sqs"}}.Matches(rel))
+	require.False(t, EdgeRule{Kind: "sync"}.Matches(rel))
+	require.False(t, EdgeRule{Label: "reads*"}.Matches(rel))
+	require.False(t, EdgeRule{Source: Match{Type: "sqs"}}.Matches(rel))
+}
+
+func TestGlobMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		value   string
+		want    bool
+	}{
+		{pattern: "", value: "anything", want: true},
+		{pattern: "*", value: "", want: true},
+		{pattern: "*", value: "arn:aws:sqs/orders", want: true},
+		{pattern: "arn:*/orders", value: "arn:aws:sqs/team/orders", want: true},
+		{pattern: "orders-*", value: "orders-eu/west", want: true},
+		{pattern: "*-queue", value: "orders-dlq-queue", want: true},
+		{pattern: "ord?rs", value: "orders", want: true},
+		{pattern: "a*b*c", value: "a/b/x/c", want: true},
+		{pattern: "a*b*c", value: "a/b/x/d", want: false},
+		{pattern: "orders", value: "orders-queue", want: false},
+		{pattern: "[", value: "[", want: true},
+	}
+
+	for i := range tests {
+		tc := tests[i]
+
+		t.Run(tc.pattern+" "+tc.value, func(t *testing.T) {
+			require.Equal(t, tc.want, globMatch(tc.pattern, tc.value))
+		})
+	}
+}
+
+func TestApplyNodeRules_WildcardsDoNotOutrankExactCriteria(t *testing.T) {
+	queue := resources.NewGenericResource("orders-queue", "orders", "sqs")
+
+	byType := NodeRule{Match: Match{Type: "sqs"}, Attrs: map[string]any{"fillcolor": "yellow"}}
+	byAnyID := NodeRule{Match: Match{ID: "*"}, Attrs: map[string]any{"fillcolor": "gray"}}
+	byIDGlob := NodeRule{Match: Match{ID: "orders-*"}, Attrs: map[string]any{"fillcolor": "orange"}}
+
+	tests := []struct {
+		name  string
+		rules []NodeRule
+		want  string
+	}{
+		{name: "type before any ID", rules: []NodeRule{byType, byAnyID}, want: "yellow"},
+		{name: "any ID before type", rules: []NodeRule{byAnyID, byType}, want: "yellow"},
+		{name: "ID glob with literals", rules: []NodeRule{byIDGlob, byType, byAnyID}, want: "orange"},
+	}
+
+	for i := range tests {
+		tc := tests[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			node := applyNodeRules(dot.NewGraph(dot.Directed).Node("n"), queue, sortNodeRules(tc.rules))
+
+			require.Equal(t, tc.want, node.Value("fillcolor"))
+		})
+	}
+}
+
+func TestSortEdgeRules(t *testing.T) {
+	byKind := EdgeRule{Kind: "async"}
+	byTarget := EdgeRule{Target: Match{Type: "sqs"}}
+	byAnyLabel := EdgeRule{Label: "*"}
+
+	rules := []EdgeRule{byKind, byTarget, byAnyLabel}
+
+	require.Equal(t, []EdgeRule{byAnyLabel, byTarget, byKind}, sortEdgeRules(rules))
+	require.Equal(t, []EdgeRule{byKind, byTarget, byAnyLabel}, rules)
+}
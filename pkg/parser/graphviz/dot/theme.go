@@ -0,0 +1,144 @@
+package dot
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Names of the theme presets.
+const (
+	ThemeLight        = "light"
+	ThemeDark         = "dark"
+	ThemePrint        = "print"
+	ThemeHighContrast = "high-contrast"
+)
+
+var ErrUnknownTheme = errors.New("unknown theme")
+
+// Theme holds the DOT attributes shared by the diagrams of a given look. The attributes of the Config take precedence
+// over the ones of its Theme.
+type Theme struct {
+	Name         string         `yaml:"name"`
+	GraphAttrs   map[string]any `yaml:"graphAttrs"`
+	NodeAttrs    map[string]any `yaml:"nodeAttrs"`
+	EdgeAttrs    map[string]any `yaml:"edgeAttrs"`
+	ClusterAttrs map[string]any `yaml:"clusterAttrs"`
+	// ResourceImageMap holds the images of the resource types missing from the Config ResourceImageMap.
+	ResourceImageMap map[string]string `yaml:"resourceImageMap"`
+}
+
+var themePresets = map[string]func() *Theme{
+	ThemeLight: func() *Theme {
+		return &Theme{
+			Name:         ThemeLight,
+			GraphAttrs:   map[string]any{"bgcolor": "white", "fontcolor": "#333333", "fontname": "Helvetica"},
+			NodeAttrs:    map[string]any{"fontcolor": "#333333", "fontname": "Helvetica"},
+			EdgeAttrs:    map[string]any{"color": "#666666", "fontcolor": "#333333", "fontname": "Helvetica"},
+			ClusterAttrs: map[string]any{"color": "#cccccc", "style": "rounded"},
+		}
+	},
+	ThemeDark: func() *Theme {
+		return &Theme{
+			Name:         ThemeDark,
+			GraphAttrs:   map[string]any{"bgcolor": "#1e1e1e", "fontcolor": "#e0e0e0", "fontname": "Helvetica"},
+			NodeAttrs:    map[string]any{"fontcolor": "#e0e0e0", "fontname": "Helvetica"},
+			EdgeAttrs:    map[string]any{"color": "#a0a0a0", "fontcolor": "#e0e0e0", "fontname": "Helvetica"},
+			ClusterAttrs: map[string]any{"color": "#555555", "style": "rounded"},
+		}
+	},
+	ThemePrint: func() *Theme {
+		return &Theme{
+			Name:         ThemePrint,
+			GraphAttrs:   map[string]any{"bgcolor": "white", "fontcolor": "black", "fontname": "Times-Roman"},
+			NodeAttrs:    map[string]any{"fontcolor": "black", "fontname": "Times-Roman"},
+			EdgeAttrs:    map[string]any{"color": "black", "fontcolor": "black", "fontname": "Times-Roman"},
+			ClusterAttrs: map[string]any{"color": "black", "style": "dashed"},
+		}
+	},
+	ThemeHighContrast: func() *Theme {
+		return &Theme{
+			Name:       ThemeHighContrast,
+			GraphAttrs: map[string]any{"bgcolor": "black", "fontcolor": "white", "fontname": "Helvetica-Bold"},
+			NodeAttrs:  map[string]any{"fontcolor": "yellow", "fontname": "Helvetica-Bold", "fontsize": 16},
+			EdgeAttrs: map[string]any{
+				"color": "white", "fontcolor": "white", "fontname": "Helvetica-Bold", "penwidth": 2,
+			},
+			ClusterAttrs: map[string]any{"color": "white", "penwidth": 2},
+		}
+	},
+}
+
+// ThemeNames returns the names of the theme presets, sorted.
+func ThemeNames() []string {
+	names := make([]string, 0, len(themePresets))
+	for name := range themePresets {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// ThemeByName returns a copy of the theme preset with the given name.
+func ThemeByName(name string) (*Theme, error) {
+	preset, ok := themePresets[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownTheme, name)
+	}
+
+	return preset(), nil
+}
+
+// ReadTheme decodes a theme in YAML or JSON. A theme whose name is a preset starts from that preset, so a file only
+// needs the attributes it changes.
+func ReadTheme(r io.Reader) (*Theme, error) {
+	var theme Theme
+
+	if err := yaml.NewDecoder(r).Decode(&theme); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	preset, ok := themePresets[theme.Name]
+	if !ok {
+		return &theme, nil
+	}
+
+	base := preset()
+	base.GraphAttrs = mergeAnyAttrs(base.GraphAttrs, theme.GraphAttrs)
+	base.NodeAttrs = mergeAnyAttrs(base.NodeAttrs, theme.NodeAttrs)
+	base.EdgeAttrs = mergeAnyAttrs(base.EdgeAttrs, theme.EdgeAttrs)
+	base.ClusterAttrs = mergeAnyAttrs(base.ClusterAttrs, theme.ClusterAttrs)
+	base.ResourceImageMap = theme.ResourceImageMap
+
+	return base, nil
+}
+
+// LoadTheme reads the theme in the given YAML or JSON file.
+func LoadTheme(path string) (*Theme, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReadTheme(f)
+}
+
+// mergeAnyAttrs returns a new map with the attributes of every map, the later maps taking precedence.
+func mergeAnyAttrs(maps ...map[string]any) map[string]any {
+	merged := map[string]any{}
+
+	for _, m := range maps {
+		for k, v := range m {
+			merged[k] = v
+		}
+	}
+
+	return merged
+}
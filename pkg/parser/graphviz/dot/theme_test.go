@@ -0,0 +1,102 @@
+package dot
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestThemeByName(t *testing.T) {
+	require.Equal(t, []string{ThemeDark, ThemeHighContrast, ThemeLight, ThemePrint}, ThemeNames())
+
+	for _, name := range ThemeNames() {
+		theme, err := ThemeByName(name)
+
+		require.NoError(t, err)
+		require.Equal(t, name, theme.Name)
+	}
+
+	// Every call returns a copy of the preset.
+	theme, _ := ThemeByName(ThemeDark)
+	theme.NodeAttrs["fontcolor"] = "red"
+
+	theme, _ = ThemeByName(ThemeDark)
+	require.Equal(t, "#e0e0e0", theme.NodeAttrs["fontcolor"])
+
+	_, err := ThemeByName("sepia")
+
+	require.ErrorIs(t, err, ErrUnknownTheme)
+}
+
+func TestReadTheme(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    *Theme
+		wantErr bool
+	}{
+		{
+			name: "custom theme",
+			input: `name: brand
+nodeAttrs:
+  fontcolor: "#ff6600"
+  fontsize: 12
+resourceImageMap:
+  lambda: images/lambda.svg
+`,
+			want: &Theme{
+				Name:             "brand",
+				NodeAttrs:        map[string]any{"fontcolor": "#ff6600", "fontsize": 12},
+				ResourceImageMap: map[string]string{"lambda": "images/lambda.svg"},
+			},
+		},
+		{
+			name:  "preset with changes in JSON",
+			input: `{"name": "print", "edgeAttrs": {"color": "gray"}}`,
+			want: &Theme{
+				Name:         ThemePrint,
+				GraphAttrs:   map[string]any{"bgcolor": "white", "fontcolor": "black", "fontname": "Times-Roman"},
+				NodeAttrs:    map[string]any{"fontcolor": "black", "fontname": "Times-Roman"},
+				EdgeAttrs:    map[string]any{"color": "gray", "fontcolor": "black", "fontname": "Times-Roman"},
+				ClusterAttrs: map[string]any{"color": "black", "style": "dashed"},
+			},
+		},
+		{name: "empty input", input: "", want: &Theme{}},
+		{name: "invalid input", input: "nodeAttrs: [", wantErr: true},
+	}
+
+	for i := range tests {
+		tc := tests[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ReadTheme(strings.NewReader(tc.input))
+
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestLoadTheme(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "theme.yaml")
+
+	require.NoError(t, os.WriteFile(path, []byte("name: dark\ngraphAttrs:\n  bgcolor: black\n"), 0o600))
+
+	theme, err := LoadTheme(path)
+
+	require.NoError(t, err)
+	require.Equal(t, "black", theme.GraphAttrs["bgcolor"])
+	require.Equal(t, "#e0e0e0", theme.GraphAttrs["fontcolor"])
+
+	_, err = LoadTheme(filepath.Join(t.TempDir(), "missing.yaml"))
+
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,289 @@
+package dotparse
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenID
+	tokenEdgeOp
+	tokenLBrace
+	tokenRBrace
+	tokenLBracket
+	tokenRBracket
+	tokenEqual
+	tokenSemicolon
+	tokenComma
+	tokenColon
+)
+
+var tokenNames = map[tokenKind]string{
+	tokenEOF:       "end of file",
+	tokenID:        "ID",
+	tokenEdgeOp:    "edge operator",
+	tokenLBrace:    "'{'",
+	tokenRBrace:    "'}'",
+	tokenLBracket:  "'['",
+	tokenRBracket:  "']'",
+	tokenEqual:     "'='",
+	tokenSemicolon: "';'",
+	tokenComma:     "','",
+	tokenColon:     "':'",
+}
+
+var punctuation = map[byte]tokenKind{
+	'{': tokenLBrace, '}': tokenRBrace, '[': tokenLBracket, ']': tokenRBracket,
+	'=': tokenEqual, ';': tokenSemicolon, ',': tokenComma, ':': tokenColon,
+}
+
+type token struct {
+	kind  tokenKind
+	value string
+	// quoted is set for double-quoted and HTML strings, which are never keywords.
+	quoted bool
+	line   int
+}
+
+// lexer splits DOT text into tokens, following https://graphviz.org/doc/info/lang.html.
+type lexer struct {
+	input string
+	pos   int
+	line  int
+}
+
+func tokenize(input string) ([]token, error) {
+	l := &lexer{input: input, line: 1}
+
+	var tokens []token
+
+	for {
+		tok, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+
+		tokens = append(tokens, tok)
+
+		if tok.kind == tokenEOF {
+			return tokens, nil
+		}
+	}
+}
+
+func (l *lexer) next() (token, error) {
+	if err := l.skipSpacesAndComments(); err != nil {
+		return token{}, err
+	}
+
+	if l.pos >= len(l.input) {
+		return token{kind: tokenEOF, line: l.line}, nil
+	}
+
+	line := l.line
+	c := l.input[l.pos]
+
+	switch {
+	case punctuation[c] != tokenEOF:
+		l.pos++
+		return token{kind: punctuation[c], value: string(c), line: line}, nil
+	case strings.HasPrefix(l.input[l.pos:], "->") || strings.HasPrefix(l.input[l.pos:], "--"):
+		l.pos += 2
+		return token{kind: tokenEdgeOp, value: l.input[l.pos-2 : l.pos], line: line}, nil
+	case c == '"':
+		value, err := l.quotedString()
+		return token{kind: tokenID, value: value, quoted: true, line: line}, err
+	case c == '<':
+		value, err := l.htmlString()
+		return token{kind: tokenID, value: value, quoted: true, line: line}, err
+	case c == '-' || c == '.' || isDigit(c):
+		value, err := l.numeral()
+		return token{kind: tokenID, value: value, line: line}, err
+	case isIDStart(c):
+		return token{kind: tokenID, value: l.identifier(), line: line}, nil
+	default:
+		r, _ := utf8.DecodeRuneInString(l.input[l.pos:])
+		return token{}, fmt.Errorf("%w: line %d: unexpected character %q", ErrSyntax, line, r)
+	}
+}
+
+func (l *lexer) skipSpacesAndComments() error {
+	for l.pos < len(l.input) {
+		rest := l.input[l.pos:]
+
+		switch {
+		case rest[0] == '\n':
+			l.line++
+			l.pos++
+		case rest[0] == ' ' || rest[0] == '\t' || rest[0] == '\r':
+			l.pos++
+		case strings.HasPrefix(rest, "//") || rest[0] == '#' && l.atLineStart():
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				end = len(rest)
+			}
+
+			l.pos += end
+		case strings.HasPrefix(rest, "/*"):
+			end := strings.Index(rest[2:], "*/")
+			if end < 0 {
+				return fmt.Errorf("%w: line %d: unterminated comment", ErrSyntax, l.line)
+			}
+
+			l.line += strings.Count(rest[:end+4], "\n")
+			l.pos += end + 4
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// atLineStart reports whether only spaces come before the current position in its line, where "#" starts a
+// preprocessor output line that must be ignored.
+func (l *lexer) atLineStart() bool {
+	start := strings.LastIndexByte(l.input[:l.pos], '\n') + 1
+	return strings.TrimSpace(l.input[start:l.pos]) == ""
+}
+
+// quotedString reads a double-quoted string, and the ones concatenated to it with "+". Only escaped quotes and
+// escaped line breaks are replaced, other escape sequences like "\n" are kept for Graphviz to interpret.
+func (l *lexer) quotedString() (string, error) {
+	var sb strings.Builder
+
+	for {
+		line := l.line
+		l.pos++ // opening quote
+
+		closed := false
+
+		for l.pos < len(l.input) && !closed {
+			c := l.input[l.pos]
+
+			switch {
+			case c == '\\' && l.pos+1 < len(l.input) && l.input[l.pos+1] == '"':
+				sb.WriteByte('"')
+				l.pos += 2
+			case c == '\\' && l.pos+1 < len(l.input) && l.input[l.pos+1] == '\n':
+				l.line++
+				l.pos += 2
+			case c == '"':
+				closed = true
+				l.pos++
+			default:
+				if c == '\n' {
+					l.line++
+				}
+
+				sb.WriteByte(c)
+				l.pos++
+			}
+		}
+
+		if !closed {
+			return "", fmt.Errorf("%w: line %d: unterminated string", ErrSyntax, line)
+		}
+
+		// Concatenation: "a" + "b".
+		saved, savedLine := l.pos, l.line
+		if err := l.skipSpacesAndComments(); err != nil {
+			return "", err
+		}
+
+		if l.pos < len(l.input) && l.input[l.pos] == '+' {
+			l.pos++
+
+			if err := l.skipSpacesAndComments(); err != nil {
+				return "", err
+			}
+
+			if l.pos < len(l.input) && l.input[l.pos] == '"' {
+				continue
+			}
+		}
+
+		l.pos, l.line = saved, savedLine
+
+		return sb.String(), nil
+	}
+}
+
+// htmlString reads an HTML string, returning the text between the outer angle brackets.
+func (l *lexer) htmlString() (string, error) {
+	line := l.line
+	start := l.pos + 1
+	depth := 0
+
+	for ; l.pos < len(l.input); l.pos++ {
+		switch l.input[l.pos] {
+		case '<':
+			depth++
+		case '>':
+			depth--
+			if depth == 0 {
+				l.pos++
+				return l.input[start : l.pos-1], nil
+			}
+		case '\n':
+			l.line++
+		}
+	}
+
+	return "", fmt.Errorf("%w: line %d: unterminated HTML string", ErrSyntax, line)
+}
+
+// numeral reads a number, e.g. "-1.5" or ".5", which must have at least one digit.
+func (l *lexer) numeral() (string, error) {
+	start := l.pos
+
+	if l.input[l.pos] == '-' {
+		l.pos++
+	}
+
+	digits, dot := 0, false
+
+	for ; l.pos < len(l.input); l.pos++ {
+		c := l.input[l.pos]
+		if c == '.' && !dot {
+			dot = true
+			continue
+		}
+
+		if !isDigit(c) {
+			break
+		}
+
+		digits++
+	}
+
+	if digits == 0 {
+		return "", fmt.Errorf("%w: line %d: invalid number %q", ErrSyntax, l.line, l.input[start:l.pos])
+	}
+
+	return l.input[start:l.pos], nil
+}
+
+func (l *lexer) identifier() string {
+	start := l.pos
+
+	for l.pos < len(l.input) && (isIDStart(l.input[l.pos]) || isDigit(l.input[l.pos])) {
+		l.pos++
+	}
+
+	return l.input[start:l.pos]
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// isIDStart reports whether c can start an unquoted ID. Bytes of multi-byte UTF-8 characters are accepted, as
+// Graphviz does.
+func isIDStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c >= 0x80
+}
@@ -0,0 +1,451 @@
+// Package dotparse reads DOT text into a Graph without Graphviz, so diagrams can be imported back into resources by
+// the dottoresources transformer.
+package dotparse
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrSyntax = errors.New("DOT syntax error")
+
+// Graph is a DOT graph read by Parse.
+type Graph struct {
+	ID       string
+	Strict   bool
+	Directed bool
+	Attrs    map[string]string
+	// Nodes holds every node, including the ones inside subgraphs, in the order they first appear.
+	Nodes []*Node
+	Edges []*Edge
+	// Subgraphs holds the top-level subgraphs.
+	Subgraphs []*Subgraph
+}
+
+// Node is a DOT node. Its attributes include the node defaults set before the node first appears.
+type Node struct {
+	ID    string
+	Attrs map[string]string
+	// Subgraph is the innermost subgraph where the node first appears, or nil for the root graph.
+	Subgraph *Subgraph
+}
+
+// Edge is a DOT edge. Edge chains like "a -> b -> c" and edges to subgraphs like "a -> {b c}" are split into one edge
+// per pair of nodes.
+type Edge struct {
+	From     string
+	FromPort string
+	To       string
+	ToPort   string
+	Attrs    map[string]string
+}
+
+// Subgraph is a DOT subgraph, e.g. "subgraph cluster_vpc { ... }".
+type Subgraph struct {
+	ID        string
+	Attrs     map[string]string
+	Parent    *Subgraph
+	Subgraphs []*Subgraph
+	// NodeIDs holds the nodes of the subgraph and of its nested subgraphs, in the order they first appear.
+	NodeIDs []string
+}
+
+// IsCluster reports whether the subgraph is a cluster, which Graphviz draws as a box around its nodes.
+func (s *Subgraph) IsCluster() bool {
+	return strings.HasPrefix(s.ID, "cluster")
+}
+
+// Node returns the node with the given ID, or nil when there is none.
+func (g *Graph) Node(id string) *Node {
+	for _, node := range g.Nodes {
+		if node.ID == id {
+			return node
+		}
+	}
+
+	return nil
+}
+
+// scope holds the state of the graph or subgraph being parsed.
+type scope struct {
+	subgraph  *Subgraph
+	attrs     map[string]string
+	nodeAttrs map[string]string
+	edgeAttrs map[string]string
+}
+
+type parser struct {
+	tokens    []token
+	pos       int
+	graph     *Graph
+	nodes     map[string]*Node
+	subgraphs map[string]*Subgraph
+}
+
+// Parse reads a DOT graph, as described in https://graphviz.org/doc/info/lang.html.
+func Parse(data []byte) (*Graph, error) {
+	tokens, err := tokenize(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, nodes: map[string]*Node{}, subgraphs: map[string]*Subgraph{}}
+
+	if err := p.parseGraph(); err != nil {
+		return nil, err
+	}
+
+	return p.graph, nil
+}
+
+func (p *parser) peek() token { return p.tokens[p.pos] }
+
+func (p *parser) advance() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+
+	return tok
+}
+
+func (p *parser) expect(kind tokenKind) (token, error) {
+	tok := p.advance()
+	if tok.kind != kind {
+		return tok, p.unexpected(tok, tokenNames[kind])
+	}
+
+	return tok, nil
+}
+
+func (p *parser) unexpected(tok token, expected string) error {
+	found := tokenNames[tok.kind]
+	if tok.kind == tokenID {
+		found = fmt.Sprintf("%q", tok.value)
+	}
+
+	return fmt.Errorf("%w: line %d: expected %s, found %s", ErrSyntax, tok.line, expected, found)
+}
+
+// isKeyword reports whether the token is the given keyword. Keywords are case-independent and never quoted.
+func isKeyword(tok token, keyword string) bool {
+	return tok.kind == tokenID && !tok.quoted && strings.EqualFold(tok.value, keyword)
+}
+
+func (p *parser) parseGraph() error {
+	p.graph = &Graph{Attrs: map[string]string{}}
+
+	if isKeyword(p.peek(), "strict") {
+		p.advance()
+		p.graph.Strict = true
+	}
+
+	switch tok := p.advance(); {
+	case isKeyword(tok, "digraph"):
+		p.graph.Directed = true
+	case isKeyword(tok, "graph"):
+	default:
+		return p.unexpected(tok, "graph or digraph")
+	}
+
+	if p.peek().kind == tokenID {
+		p.graph.ID = p.advance().value
+	}
+
+	if _, err := p.expect(tokenLBrace); err != nil {
+		return err
+	}
+
+	root := &scope{attrs: p.graph.Attrs, nodeAttrs: map[string]string{}, edgeAttrs: map[string]string{}}
+
+	if _, err := p.parseStmtList(root); err != nil {
+		return err
+	}
+
+	if _, err := p.expect(tokenRBrace); err != nil {
+		return err
+	}
+
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return p.unexpected(tok, tokenNames[tokenEOF])
+	}
+
+	return nil
+}
+
+// parseStmtList parses the statements until the closing brace, returning the IDs of the nodes they reference.
+func (p *parser) parseStmtList(s *scope) ([]string, error) {
+	var nodeIDs []string
+
+	for {
+		tok := p.peek()
+
+		switch {
+		case tok.kind == tokenRBrace || tok.kind == tokenEOF:
+			return nodeIDs, nil
+		case tok.kind == tokenSemicolon:
+			p.advance()
+			continue
+		}
+
+		ids, err := p.parseStmt(s)
+		if err != nil {
+			return nil, err
+		}
+
+		nodeIDs = append(nodeIDs, ids...)
+	}
+}
+
+func (p *parser) parseStmt(s *scope) ([]string, error) {
+	tok := p.peek()
+
+	switch {
+	case isKeyword(tok, "graph"), isKeyword(tok, "node"), isKeyword(tok, "edge"):
+		p.advance()
+
+		attrs, err := p.parseAttrLists()
+		if err != nil {
+			return nil, err
+		}
+
+		target := map[string]map[string]string{"graph": s.attrs, "node": s.nodeAttrs, "edge": s.edgeAttrs}
+		mergeAttrs(target[strings.ToLower(tok.value)], attrs)
+
+		return nil, nil
+	case tok.kind == tokenID && p.tokens[p.pos+1].kind == tokenEqual:
+		p.advance()
+		p.advance()
+
+		value, err := p.expect(tokenID)
+		if err != nil {
+			return nil, err
+		}
+
+		s.attrs[tok.value] = value.value
+
+		return nil, nil
+	}
+
+	return p.parseNodeOrEdgeStmt(s)
+}
+
+// endpoint is a node or a subgraph at one side of an edge operator.
+type endpoint struct {
+	nodeIDs []string
+	port    string
+}
+
+func (p *parser) parseNodeOrEdgeStmt(s *scope) ([]string, error) {
+	first, isNode, err := p.parseEndpoint(s)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := []endpoint{first}
+
+	for p.peek().kind == tokenEdgeOp {
+		p.advance()
+
+		next, _, err := p.parseEndpoint(s)
+		if err != nil {
+			return nil, err
+		}
+
+		endpoints = append(endpoints, next)
+	}
+
+	attrs, err := p.parseAttrLists()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(endpoints) == 1 {
+		if isNode {
+			mergeAttrs(p.nodes[first.nodeIDs[0]].Attrs, attrs)
+		}
+
+		return first.nodeIDs, nil
+	}
+
+	var nodeIDs []string
+
+	for i := 0; i < len(endpoints)-1; i++ {
+		from, to := endpoints[i], endpoints[i+1]
+
+		for _, fromID := range from.nodeIDs {
+			for _, toID := range to.nodeIDs {
+				edgeAttrs := copyAttrs(s.edgeAttrs)
+				mergeAttrs(edgeAttrs, attrs)
+
+				p.graph.Edges = append(p.graph.Edges, &Edge{
+					From: fromID, FromPort: from.port, To: toID, ToPort: to.port, Attrs: edgeAttrs,
+				})
+			}
+		}
+
+		nodeIDs = append(nodeIDs, from.nodeIDs...)
+	}
+
+	return append(nodeIDs, endpoints[len(endpoints)-1].nodeIDs...), nil
+}
+
+// parseEndpoint parses a node ID with an optional port, or a subgraph. It reports whether it is a node.
+func (p *parser) parseEndpoint(s *scope) (endpoint, bool, error) {
+	tok := p.peek()
+
+	if isKeyword(tok, "subgraph") || tok.kind == tokenLBrace {
+		nodeIDs, err := p.parseSubgraph(s)
+		return endpoint{nodeIDs: nodeIDs}, false, err
+	}
+
+	id, err := p.expect(tokenID)
+	if err != nil {
+		return endpoint{}, false, err
+	}
+
+	var ports []string
+
+	for p.peek().kind == tokenColon {
+		p.advance()
+
+		port, err := p.expect(tokenID)
+		if err != nil {
+			return endpoint{}, false, err
+		}
+
+		ports = append(ports, port.value)
+	}
+
+	p.addNode(s, id.value)
+
+	return endpoint{nodeIDs: []string{id.value}, port: strings.Join(ports, ":")}, true, nil
+}
+
+func (p *parser) parseSubgraph(parent *scope) ([]string, error) {
+	var id string
+
+	if isKeyword(p.peek(), "subgraph") {
+		p.advance()
+
+		if p.peek().kind == tokenID {
+			id = p.advance().value
+		}
+	}
+
+	if _, err := p.expect(tokenLBrace); err != nil {
+		return nil, err
+	}
+
+	sub, ok := p.subgraphs[id]
+	if !ok {
+		sub = &Subgraph{ID: id, Attrs: map[string]string{}, Parent: parent.subgraph}
+
+		if parent.subgraph == nil {
+			p.graph.Subgraphs = append(p.graph.Subgraphs, sub)
+		} else {
+			parent.subgraph.Subgraphs = append(parent.subgraph.Subgraphs, sub)
+		}
+
+		if id != "" {
+			p.subgraphs[id] = sub
+		}
+	}
+
+	s := &scope{
+		subgraph:  sub,
+		attrs:     sub.Attrs,
+		nodeAttrs: copyAttrs(parent.nodeAttrs),
+		edgeAttrs: copyAttrs(parent.edgeAttrs),
+	}
+
+	nodeIDs, err := p.parseStmtList(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := p.expect(tokenRBrace); err != nil {
+		return nil, err
+	}
+
+	return nodeIDs, nil
+}
+
+// parseAttrLists parses any number of attribute lists, e.g. [shape=box, color=red][label="x"].
+func (p *parser) parseAttrLists() (map[string]string, error) {
+	attrs := map[string]string{}
+
+	for p.peek().kind == tokenLBracket {
+		p.advance()
+
+		for p.peek().kind != tokenRBracket {
+			key, err := p.expect(tokenID)
+			if err != nil {
+				return nil, err
+			}
+
+			value := "true"
+
+			if p.peek().kind == tokenEqual {
+				p.advance()
+
+				tok, err := p.expect(tokenID)
+				if err != nil {
+					return nil, err
+				}
+
+				value = tok.value
+			}
+
+			attrs[key.value] = value
+
+			if kind := p.peek().kind; kind == tokenComma || kind == tokenSemicolon {
+				p.advance()
+			}
+		}
+
+		p.advance()
+	}
+
+	return attrs, nil
+}
+
+// addNode creates the node when it first appears and adds it to the subgraph of the scope and its ancestors.
+func (p *parser) addNode(s *scope, id string) {
+	if _, ok := p.nodes[id]; !ok {
+		node := &Node{ID: id, Attrs: copyAttrs(s.nodeAttrs), Subgraph: s.subgraph}
+
+		p.nodes[id] = node
+		p.graph.Nodes = append(p.graph.Nodes, node)
+	}
+
+	for sub := s.subgraph; sub != nil; sub = sub.Parent {
+		if !contains(sub.NodeIDs, id) {
+			sub.NodeIDs = append(sub.NodeIDs, id)
+		}
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func copyAttrs(attrs map[string]string) map[string]string {
+	result := make(map[string]string, len(attrs))
+	mergeAttrs(result, attrs)
+
+	return result
+}
+
+func mergeAttrs(dst, src map[string]string) {
+	for k, v := range src {
+		dst[k] = v
+	}
+}
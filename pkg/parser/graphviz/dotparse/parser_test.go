@@ -0,0 +1,179 @@
+package dotparse
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		check func(t *testing.T, g *Graph)
+	}{
+		{
+			name: "nodes, edges and attributes",
+			input: `/* A comment */
+strict digraph "My Graph" {
+	# preprocessor line
+	rankdir=LR; // trailing comment
+	node [shape=plaintext, height=0.9]
+	edge [arrowhead=vee]
+	MyLambda [image="images/lambda.svg" label="My" + " Lambda"];
+	"my-queue" [image="images/sqs.svg"][tooltip=<<b>queue</b>>]
+	MyLambda -> "my-queue" -> MyStream [label="writes to", style=dashed]
+	MyLambda:out:e -> doc:in
+	n1 [label="say \"hi\""]
+}`,
+			check: func(t *testing.T, g *Graph) {
+				require.True(t, g.Strict)
+				require.True(t, g.Directed)
+				require.Equal(t, "My Graph", g.ID)
+				require.Equal(t, map[string]string{"rankdir": "LR"}, g.Attrs)
+
+				require.Len(t, g.Nodes, 5)
+				require.Equal(t, &Node{ID: "MyLambda", Attrs: map[string]string{
+					"shape": "plaintext", "height": "0.9", "image": "images/lambda.svg", "label": "My Lambda",
+				}}, g.Nodes[0])
+				require.Equal(t, map[string]string{
+					"shape": "plaintext", "height": "0.9", "image": "images/sqs.svg", "tooltip": "<b>queue</b>",
+				}, g.Node("my-queue").Attrs)
+				require.Equal(t, `say "hi"`, g.Node("n1").Attrs["label"])
+				require.Nil(t, g.Node("unknown"))
+
+				edgeAttrs := map[string]string{"arrowhead": "vee", "label": "writes to", "style": "dashed"}
+				require.Equal(t, []*Edge{
+					{From: "MyLambda", To: "my-queue", Attrs: edgeAttrs},
+					{From: "my-queue", To: "MyStream", Attrs: edgeAttrs},
+					{From: "MyLambda", FromPort: "out:e", To: "doc", ToPort: "in", Attrs: map[string]string{
+						"arrowhead": "vee",
+					}},
+				}, g.Edges)
+			},
+		},
+		{
+			name: "subgraphs",
+			input: `graph {
+	node [shape=box]
+	subgraph cluster_vpc {
+		label="My VPC"
+		node [shape=ellipse]
+		subgraph cluster_subnet {
+			graph [style=filled]
+			lambda
+		}
+		queue
+	}
+	stream -- { lambda queue }
+	subgraph cluster_vpc { other }
+	orphan
+}`,
+			check: func(t *testing.T, g *Graph) {
+				require.False(t, g.Directed)
+				require.Len(t, g.Subgraphs, 2)
+
+				// The edge target is an anonymous subgraph.
+				require.False(t, g.Subgraphs[1].IsCluster())
+				require.Equal(t, []string{"lambda", "queue"}, g.Subgraphs[1].NodeIDs)
+
+				vpc := g.Subgraphs[0]
+				require.True(t, vpc.IsCluster())
+				require.Equal(t, map[string]string{"label": "My VPC"}, vpc.Attrs)
+				require.Equal(t, []string{"lambda", "queue", "other"}, vpc.NodeIDs)
+				require.Len(t, vpc.Subgraphs, 1)
+
+				subnet := vpc.Subgraphs[0]
+				require.Equal(t, vpc, subnet.Parent)
+				require.Equal(t, map[string]string{"style": "filled"}, subnet.Attrs)
+				require.Equal(t, subnet, g.Node("lambda").Subgraph)
+				require.Equal(t, vpc, g.Node("queue").Subgraph)
+				require.Nil(t, g.Node("orphan").Subgraph)
+				require.Equal(t, map[string]string{"shape": "ellipse"}, g.Node("lambda").Attrs)
+				require.Equal(t, map[string]string{"shape": "box"}, g.Node("orphan").Attrs)
+
+				require.Equal(t, []*Edge{
+					{From: "stream", To: "lambda", Attrs: map[string]string{}},
+					{From: "stream", To: "queue", Attrs: map[string]string{}},
+				}, g.Edges)
+			},
+		},
+	}
+
+	for i := range tests {
+		tc := tests[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			g, err := Parse([]byte(tc.input))
+
+			require.NoError(t, err)
+			tc.check(t, g)
+		})
+	}
+}
+
+func TestParse_GeneratedDOT(t *testing.T) {
+	// The DOT built by the dot package.
+	for _, fileName := range []string{
+		"happy_path.dot", "with_style/happy_path.dot", "containers.dot", "clusters_by_type.dot",
+		"relationship_labels.dot",
+	} {
+		data, err := os.ReadFile(filepath.Join("..", "dot", "testdata", fileName))
+		require.NoError(t, err)
+
+		_, err = Parse(data)
+
+		require.NoError(t, err, fileName)
+	}
+
+	containersDot, err := os.ReadFile(filepath.Join("..", "dot", "testdata", "containers.dot"))
+	require.NoError(t, err)
+
+	g, err := Parse(containersDot)
+
+	require.NoError(t, err)
+	require.Equal(t, "cluster_s1", g.Subgraphs[0].ID)
+	require.Equal(t, "my-vpc", g.Subgraphs[0].Attrs["label"])
+	require.Equal(t, "cluster_s1", g.Edges[1].Attrs["lhead"])
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "missing graph keyword", input: `{ a }`, wantErr: `line 1: expected graph or digraph, found '{'`},
+		{
+			name:    "missing closing brace",
+			input:   "digraph {\n a -> b",
+			wantErr: `line 2: expected '}', found end of file`,
+		},
+		{name: "unterminated string", input: `digraph { "a }`, wantErr: `line 1: unterminated string`},
+		{name: "unterminated HTML string", input: `digraph { a [label=<<b> }`, wantErr: `unterminated HTML string`},
+		{name: "unexpected character", input: `digraph { a @ b }`, wantErr: `unexpected character '@'`},
+		{
+			name:    "unterminated comment",
+			input:   "digraph {\n a /* b -> c }",
+			wantErr: `line 2: unterminated comment`,
+		},
+		{name: "lone minus", input: `digraph { a -> - }`, wantErr: `line 1: invalid number "-"`},
+		{name: "lone dot", input: "digraph {\n a [width=.] }", wantErr: `line 2: invalid number "."`},
+		{name: "missing attribute value", input: `digraph { a [label=] }`, wantErr: `expected ID, found ']'`},
+		{name: "missing edge target", input: `digraph { a -> }`, wantErr: `expected ID, found '}'`},
+		{name: "text after the graph", input: `digraph { } x`, wantErr: `expected end of file, found "x"`},
+	}
+
+	for i := range tests {
+		tc := tests[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse([]byte(tc.input))
+
+			require.ErrorIs(t, err, ErrSyntax)
+			require.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"image/jpeg"
+	"io"
+)
+
+// writeJPEGAsPDF writes a single page PDF document with the JPEG image filling the whole page. The page size is the
+// image size, taking one pixel as one point, as Graphviz does for its 72 DPI output.
+func writeJPEGAsPDF(w io.Writer, jpegData []byte) error {
+	config, err := jpeg.DecodeConfig(bytes.NewReader(jpegData))
+	if err != nil {
+		return fmt.Errorf("decoding JPEG: %w", err)
+	}
+
+	colorSpace := "/DeviceRGB"
+	if config.ColorModel == color.GrayModel {
+		colorSpace = "/DeviceGray"
+	}
+
+	content := fmt.Sprintf("q %d 0 0 %d 0 0 cm /Im0 Do Q", config.Width, config.Height)
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] "+
+			"/Resources << /XObject << /Im0 4 0 R >> >> /Contents 5 0 R >>", config.Width, config.Height),
+		fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace %s "+
+			"/BitsPerComponent 8 /Filter /DCTDecode /Length %d >>\nstream\n%s\nendstream",
+			config.Width, config.Height, colorSpace, len(jpegData), jpegData),
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+	}
+
+	var buf bytes.Buffer
+
+	buf.WriteString("%PDF-1.4\n")
+
+	offsets := make([]int, len(objects))
+
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := buf.Len()
+
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	_, err = w.Write(buf.Bytes())
+
+	return err
+}
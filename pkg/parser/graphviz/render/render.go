@@ -0,0 +1,121 @@
+// Package render renders DOT diagrams to images and layouts with the Graphviz library embedded by go-graphviz, so
+// Graphviz doesn't need to be installed on the host.
+package render
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/goccy/go-graphviz"
+
+	"github.com/diagram-code-generator/resources/pkg/parser/graphviz/dot"
+	"github.com/diagram-code-generator/resources/pkg/resources"
+)
+
+type Format string
+
+// Output formats.
+const (
+	FormatPNG Format = "png"
+	FormatJPG Format = "jpg"
+	FormatSVG Format = "svg"
+	// FormatRasterPDF is a single page PDF holding the diagram as a JPEG image, not vector graphics: the Graphviz
+	// embedded by go-graphviz has no PDF renderer. Its text can't be selected and it gets blurry when zoomed in; use
+	// FormatSVG for a vector output.
+	FormatRasterPDF Format = "pdf"
+	// FormatJSON is the Graphviz JSON output, with the positions computed by the layout engine.
+	FormatJSON Format = "json"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported format")
+
+var graphvizLayouts = map[dot.Engine]graphviz.Layout{
+	dot.EngineDot:   graphviz.DOT,
+	dot.EngineNeato: graphviz.NEATO,
+	dot.EngineFDP:   graphviz.FDP,
+	dot.EngineCirco: graphviz.CIRCO,
+	dot.EngineTwopi: graphviz.TWOPI,
+}
+
+// GraphvizLayout returns the go-graphviz layout of an engine, or of dot.DefaultEngine when it is empty.
+func GraphvizLayout(engine dot.Engine) (graphviz.Layout, error) {
+	if engine == "" {
+		engine = dot.DefaultEngine
+	}
+
+	layout, ok := graphvizLayouts[engine]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", dot.ErrUnsupportedEngine, engine)
+	}
+
+	return layout, nil
+}
+
+type Renderer struct {
+	engine dot.Engine
+}
+
+// NewRenderer creates a Renderer that lays the diagrams out with the given engine, or dot.DefaultEngine when it is
+// empty.
+func NewRenderer(engine dot.Engine) *Renderer {
+	if engine == "" {
+		engine = dot.DefaultEngine
+	}
+
+	return &Renderer{engine: engine}
+}
+
+// RenderDiagram builds the DOT text of the resource collection with the diagram and renders it to w.
+func (r *Renderer) RenderDiagram(
+	w io.Writer, diagram *dot.DotDiagram, resc *resources.ResourceCollection, format Format,
+) error {
+	dotText, err := diagram.BuildE(resc)
+	if err != nil {
+		return err
+	}
+
+	return r.Render(w, []byte(dotText), format)
+}
+
+// Render lays out the DOT text and writes it to w in the given format.
+func (r *Renderer) Render(w io.Writer, dotText []byte, format Format) (err error) {
+	layout, err := GraphvizLayout(r.engine)
+	if err != nil {
+		return err
+	}
+
+	var graphvizFormat graphviz.Format
+
+	switch format {
+	case FormatPNG, FormatJPG, FormatSVG, FormatJSON:
+		graphvizFormat = graphviz.Format(format)
+	case FormatRasterPDF:
+		graphvizFormat = graphviz.JPG
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+
+	graph, err := graphviz.ParseBytes(dotText)
+	if err != nil {
+		return fmt.Errorf("parsing DOT: %w", err)
+	}
+
+	g := graphviz.New().SetLayout(layout)
+
+	defer func() {
+		err = errors.Join(err, graph.Close(), g.Close())
+	}()
+
+	if format != FormatRasterPDF {
+		return g.Render(graph, graphvizFormat, w)
+	}
+
+	var buf bytes.Buffer
+	if err := g.Render(graph, graphvizFormat, &buf); err != nil {
+		return err
+	}
+
+	return writeJPEGAsPDF(w, buf.Bytes())
+}
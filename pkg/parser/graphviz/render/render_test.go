@@ -0,0 +1,130 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/diagram-code-generator/resources/pkg/parser/graphviz/dot"
+	"github.com/diagram-code-generator/resources/pkg/resources"
+)
+
+type failingWriter struct{ err error }
+
+func (w *failingWriter) Write(_ []byte) (int, error) { return 0, w.err }
+
+const dotText = `digraph { MyLambda -> "my-queue" }`
+
+func TestRenderer_Render(t *testing.T) {
+	tests := []struct {
+		name       string
+		engine     dot.Engine
+		format     Format
+		dotText    string
+		wantPrefix []byte
+		targetErr  error
+	}{
+		{name: "png", format: FormatPNG, dotText: dotText, wantPrefix: []byte("\x89PNG")},
+		{name: "jpg", format: FormatJPG, dotText: dotText, wantPrefix: []byte("\xff\xd8\xff")},
+		{name: "svg", format: FormatSVG, dotText: dotText, wantPrefix: []byte("<?xml")},
+		{name: "pdf", format: FormatRasterPDF, dotText: dotText, wantPrefix: []byte("%PDF-1.4")},
+		{name: "json", format: FormatJSON, dotText: dotText, wantPrefix: []byte("{")},
+		{
+			name: "neato engine", engine: dot.EngineNeato, format: FormatSVG, dotText: dotText,
+			wantPrefix: []byte("<?xml"),
+		},
+		{name: "fdp engine", engine: dot.EngineFDP, format: FormatSVG, dotText: dotText, wantPrefix: []byte("<?xml")},
+		{
+			name: "circo engine", engine: dot.EngineCirco, format: FormatSVG, dotText: dotText,
+			wantPrefix: []byte("<?xml"),
+		},
+		{name: "unsupported format", format: "gif", dotText: dotText, targetErr: ErrUnsupportedFormat},
+		{
+			name: "unsupported engine", engine: "unknown", format: FormatSVG, dotText: dotText,
+			targetErr: dot.ErrUnsupportedEngine,
+		},
+	}
+
+	// Invalid DOT text fails without writing anything.
+	var buf bytes.Buffer
+
+	require.Error(t, NewRenderer("").Render(&buf, []byte("digraph {"), FormatSVG))
+	require.Empty(t, buf.Bytes())
+
+	for i := range tests {
+		tc := tests[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := NewRenderer(tc.engine).Render(&buf, []byte(tc.dotText), tc.format)
+
+			require.ErrorIs(t, err, tc.targetErr)
+			require.True(t, bytes.HasPrefix(buf.Bytes(), tc.wantPrefix))
+		})
+	}
+}
+
+func TestRenderer_RenderJSONLayout(t *testing.T) {
+	var buf bytes.Buffer
+
+	require.NoError(t, NewRenderer(dot.EngineDot).Render(&buf, []byte(dotText), FormatJSON))
+
+	var layout struct {
+		Objects []struct {
+			Name string `json:"name"`
+			Pos  string `json:"pos"`
+		} `json:"objects"`
+	}
+
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &layout))
+	require.Len(t, layout.Objects, 2)
+	require.Equal(t, "MyLambda", layout.Objects[0].Name)
+	require.NotEmpty(t, layout.Objects[0].Pos)
+}
+
+func TestRenderer_RenderWriterError(t *testing.T) {
+	errDummy := errors.New("dummy error")
+
+	for _, format := range []Format{FormatSVG, FormatRasterPDF} {
+		err := NewRenderer("").Render(&failingWriter{err: errDummy}, []byte(dotText), format)
+
+		require.ErrorIs(t, err, errDummy)
+	}
+}
+
+func TestRenderer_RenderDiagram(t *testing.T) {
+	lambda := resources.NewGenericResource("1", "MyLambda", "lambda")
+	queue := resources.NewGenericResource("2", "my-queue", "sqs")
+
+	t.Run("happy path", func(t *testing.T) {
+		resc := &resources.ResourceCollection{
+			Resources:     []resources.Resource{lambda, queue},
+			Relationships: []resources.Relationship{{Source: lambda, Target: queue}},
+		}
+
+		var buf bytes.Buffer
+
+		err := NewRenderer(dot.EngineDot).RenderDiagram(&buf, dot.NewDotDiagram(nil), resc, FormatSVG)
+
+		require.NoError(t, err)
+		require.Contains(t, buf.String(), "MyLambda")
+	})
+
+	t.Run("invalid collection", func(t *testing.T) {
+		resc := &resources.ResourceCollection{
+			Resources:     []resources.Resource{lambda},
+			Relationships: []resources.Relationship{{Source: lambda, Target: queue}},
+		}
+
+		var buf bytes.Buffer
+
+		err := NewRenderer(dot.EngineDot).RenderDiagram(&buf, dot.NewDotDiagram(nil), resc, FormatSVG)
+
+		require.ErrorIs(t, err, resources.ErrDanglingEndpoint)
+		require.Empty(t, buf.Bytes())
+	})
+}
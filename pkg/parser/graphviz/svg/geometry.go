@@ -0,0 +1,160 @@
+package drawioxml
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Classes of the groups of the SVG.
+const (
+	ClassNode    = "node"
+	ClassEdge    = "edge"
+	ClassCluster = "cluster"
+)
+
+var ErrInvalidGeometry = errors.New("invalid geometry")
+
+type Point struct {
+	X float64
+	Y float64
+}
+
+// Bounds is a rectangle from its top-left corner.
+type Bounds struct {
+	X      float64
+	Y      float64
+	Width  float64
+	Height float64
+}
+
+// Center returns the center of the rectangle.
+func (b Bounds) Center() Point {
+	return Point{X: b.X + b.Width/2, Y: b.Y + b.Height/2}
+}
+
+// Bounds returns the bounding box of the shape of a node or cluster: its first polygon, its ellipse or its image.
+func (n Node) Bounds() (Bounds, bool) {
+	if len(n.Polygons) > 0 {
+		points, err := n.Polygons[0].Vertices()
+		if err == nil && len(points) > 0 {
+			return boundsOf(points), true
+		}
+	}
+
+	if n.Ellipse != nil {
+		if b, err := n.Ellipse.Bounds(); err == nil {
+			return b, true
+		}
+	}
+
+	if n.Image != nil {
+		if b, err := n.Image.Bounds(); err == nil {
+			return b, true
+		}
+	}
+
+	return Bounds{}, false
+}
+
+// Vertices returns the vertices of the polygon.
+func (p Polygon) Vertices() ([]Point, error) {
+	return parsePoints(p.Points)
+}
+
+// Bounds returns the bounding box of the ellipse.
+func (e Ellipse) Bounds() (Bounds, error) {
+	values, err := parseFloats(e.CX, e.CY, e.RX, e.RY)
+	if err != nil {
+		return Bounds{}, err
+	}
+
+	cx, cy, rx, ry := values[0], values[1], values[2], values[3]
+
+	return Bounds{X: cx - rx, Y: cy - ry, Width: 2 * rx, Height: 2 * ry}, nil
+}
+
+// Bounds returns the position and size of the image. Sizes in points, e.g. "40px" or "40pt", are read as numbers.
+func (i Image) Bounds() (Bounds, error) {
+	values, err := parseFloats(i.X, i.Y, trimUnit(i.Width), trimUnit(i.Height))
+	if err != nil {
+		return Bounds{}, err
+	}
+
+	return Bounds{X: values[0], Y: values[1], Width: values[2], Height: values[3]}, nil
+}
+
+// Points returns the points of the path: its start followed by the control and end points of its Bézier curves, as
+// drawn by Graphviz with "M" and "C" commands.
+func (p Path) Points() ([]Point, error) {
+	d := strings.NewReplacer("M", " ", "C", " ", "L", " ").Replace(p.D)
+
+	return parsePoints(d)
+}
+
+// ControlPoints returns the points of the path of an edge, or nil when it has none.
+func (n Node) ControlPoints() []Point {
+	if n.Path == nil {
+		return nil
+	}
+
+	points, err := n.Path.Points()
+	if err != nil {
+		return nil
+	}
+
+	return points
+}
+
+// parsePoints parses a list of "x,y" pairs separated by spaces.
+func parsePoints(s string) ([]Point, error) {
+	fields := strings.Fields(s)
+	points := make([]Point, 0, len(fields))
+
+	for _, field := range fields {
+		x, y, ok := strings.Cut(field, ",")
+		if !ok {
+			return nil, fmt.Errorf("%w: point %q", ErrInvalidGeometry, field)
+		}
+
+		values, err := parseFloats(x, y)
+		if err != nil {
+			return nil, err
+		}
+
+		points = append(points, Point{X: values[0], Y: values[1]})
+	}
+
+	return points, nil
+}
+
+func parseFloats(values ...string) ([]float64, error) {
+	floats := make([]float64, len(values))
+
+	for i, v := range values {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: number %q", ErrInvalidGeometry, v)
+		}
+
+		floats[i] = f
+	}
+
+	return floats, nil
+}
+
+func trimUnit(v string) string {
+	return strings.TrimRight(v, "ptx")
+}
+
+func boundsOf(points []Point) Bounds {
+	minX, minY, maxX, maxY := points[0].X, points[0].Y, points[0].X, points[0].Y
+
+	for _, p := range points[1:] {
+		minX, minY = min(minX, p.X), min(minY, p.Y)
+		maxX, maxY = max(maxX, p.X), max(maxY, p.Y)
+	}
+
+	return Bounds{X: minX, Y: minY, Width: maxX - minX, Height: maxY - minY}
+}
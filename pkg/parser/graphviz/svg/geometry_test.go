@@ -0,0 +1,70 @@
+package drawioxml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNode_Bounds(t *testing.T) {
+	tests := []struct {
+		name   string
+		node   Node
+		want   Bounds
+		wantOK bool
+	}{
+		{
+			name:   "polygon",
+			node:   Node{Polygons: []Polygon{{Points: "90,-116 50,-116 50,-76 90,-76 90,-116"}}},
+			want:   Bounds{X: 50, Y: -116, Width: 40, Height: 40},
+			wantOK: true,
+		},
+		{
+			name:   "ellipse",
+			node:   Node{Ellipse: &Ellipse{CX: "96.5", CY: "-90", RX: "20", RY: "18"}},
+			want:   Bounds{X: 76.5, Y: -108, Width: 40, Height: 36},
+			wantOK: true,
+		},
+		{
+			name:   "image",
+			node:   Node{Image: &Image{Href: "lambda.svg", X: "10", Y: "-50", Width: "32px", Height: "32pt"}},
+			want:   Bounds{X: 10, Y: -50, Width: 32, Height: 32},
+			wantOK: true,
+		},
+		{
+			name:   "invalid polygon falls back to the ellipse",
+			node:   Node{Polygons: []Polygon{{Points: "90"}}, Ellipse: &Ellipse{CX: "0", CY: "0", RX: "1", RY: "1"}},
+			want:   Bounds{X: -1, Y: -1, Width: 2, Height: 2},
+			wantOK: true,
+		},
+		{name: "no shape", node: Node{}, wantOK: false},
+	}
+
+	for i := range tests {
+		tc := tests[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := tc.node.Bounds()
+
+			require.Equal(t, tc.wantOK, ok)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestBounds_Center(t *testing.T) {
+	require.Equal(t, Point{X: 70, Y: -96}, Bounds{X: 50, Y: -116, Width: 40, Height: 40}.Center())
+}
+
+func TestPath_Points(t *testing.T) {
+	points, err := Path{D: "M70.2,-75.6C70.2,-67.8 70.2,-58.7 70.2,-50.1"}.Points()
+
+	require.NoError(t, err)
+	require.Equal(t, []Point{{70.2, -75.6}, {70.2, -67.8}, {70.2, -58.7}, {70.2, -50.1}}, points)
+
+	_, err = Path{D: "M70.2,abc"}.Points()
+
+	require.ErrorIs(t, err, ErrInvalidGeometry)
+	require.Nil(t, Node{Path: &Path{D: "M1"}}.ControlPoints())
+	require.Nil(t, Node{}.ControlPoints())
+}
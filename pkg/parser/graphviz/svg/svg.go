@@ -3,43 +3,175 @@ package drawioxml
 import (
 	"bytes"
 	"encoding/xml"
+	"errors"
 	"fmt"
 
+	"github.com/diagram-code-generator/resources/pkg/parser/graphviz/dot"
+	"github.com/diagram-code-generator/resources/pkg/parser/graphviz/render"
 	"github.com/diagram-code-generator/resources/pkg/resources"
 
 	"github.com/goccy/go-graphviz"
 	"github.com/goccy/go-graphviz/cgraph"
 )
 
+// Help tests.
+var xmlUnmarshal = xml.Unmarshal
+
 const ResourceInfoSeparator = "$$"
 
+// ClusterPrefix prefixes the name of the cluster created for each container resource.
+const ClusterPrefix = "cluster_"
+
+// NodeSize is the width and height, in points, of the box laid out for each resource. Longer labels overflow the box
+// instead of growing it.
+const NodeSize = 40
+
+// pointsPerInch converts NodeSize to the inches Graphviz expects for the node sizes.
+const pointsPerInch = 72.0
+
+// Config holds the options of the Graphviz layout. Empty fields keep the Graphviz defaults.
+type Config struct {
+	Engine    dot.Engine
+	Direction dot.DiagramDirection
+	// NodeSep and RankSep are the space between the nodes of a rank and between the ranks, in inches.
+	NodeSep float64
+	RankSep float64
+}
+
 type SVGDiagram struct {
-	g *graphviz.Graphviz
+	config *Config
 }
 
 func NewSVGDiagram() *SVGDiagram {
-	return &SVGDiagram{g: graphviz.New()}
+	return NewSVGDiagramWithConfig(nil)
 }
 
-func (d *SVGDiagram) Build(resCollection *resources.ResourceCollection) *SVG {
-	graph, _ := d.g.Graph(graphviz.Directed)
+// NewSVGDiagramWithConfig creates an SVGDiagram laid out with the options of the config. A nil config keeps the
+// Graphviz defaults, like NewSVGDiagram.
+func NewSVGDiagramWithConfig(config *Config) *SVGDiagram {
+	if config == nil {
+		config = &Config{}
+	}
+
+	return &SVGDiagram{config: config}
+}
+
+// Build lays out the resources with Graphviz and returns the SVG output. Relationships with a nil endpoint are left
+// out. Each call uses its own Graphviz context, which is closed with the graph before returning.
+func (d *SVGDiagram) Build(resCollection *resources.ResourceCollection) (_ *SVG, err error) {
+	layout, err := render.GraphvizLayout(d.config.Engine)
+	if err != nil {
+		return nil, err
+	}
+
+	g := graphviz.New().SetLayout(layout)
+
+	graph, err := g.Graph(graphviz.Directed)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("creating graph: %w", err), g.Close())
+	}
+
+	defer func() {
+		err = errors.Join(err, graph.Close(), g.Close())
+	}()
+
+	d.applyGraphAttrs(graph)
+
+	subgraphs := map[string]*cgraph.Graph{}
 
 	nodesByResourceID := map[string]*cgraph.Node{}
 	for _, res := range resCollection.Resources {
-		node, _ := graph.CreateNode(fmt.Sprintf("%s%s%s%s%s",
-			res.ID(), ResourceInfoSeparator, res.Value(), ResourceInfoSeparator, res.ResourceType()))
+		// Containers are laid out as clusters around their children instead of nodes.
+		if resCollection.IsContainer(res) {
+			continue
+		}
+
+		parentGraph := subgraphFor(graph, resCollection, res, subgraphs, len(resCollection.Resources))
+
+		node, err := parentGraph.CreateNode(NodeName(res))
+		if err != nil {
+			return nil, fmt.Errorf("creating node of resource %s: %w", res.ID(), err)
+		}
+
+		node.SetShape(cgraph.BoxShape).SetWidth(NodeSize / pointsPerInch).SetHeight(NodeSize / pointsPerInch)
+		node.SafeSet("fixedsize", "shape", "false")
 		nodesByResourceID[res.ID()] = node
 	}
 
 	for _, rel := range resCollection.Relationships {
-		_, _ = graph.CreateEdge("", nodesByResourceID[rel.Source.ID()], nodesByResourceID[rel.Target.ID()])
+		// Relationships without both endpoints have no edge to lay out.
+		if rel.Source == nil || rel.Target == nil {
+			continue
+		}
+
+		source, target := nodesByResourceID[rel.Source.ID()], nodesByResourceID[rel.Target.ID()]
+		if source == nil || target == nil {
+			continue
+		}
+
+		if _, err := graph.CreateEdge("", source, target); err != nil {
+			return nil, fmt.Errorf("creating edge from %s to %s: %w", rel.Source.ID(), rel.Target.ID(), err)
+		}
 	}
 
 	var buf bytes.Buffer
-	_ = d.g.Render(graph, "svg", &buf)
+	if err := g.Render(graph, graphviz.SVG, &buf); err != nil {
+		return nil, fmt.Errorf("rendering SVG: %w", err)
+	}
 
 	var svgData SVG
-	_ = xml.Unmarshal(buf.Bytes(), &svgData)
+	if err := xmlUnmarshal(buf.Bytes(), &svgData); err != nil {
+		return nil, fmt.Errorf("parsing SVG: %w", err)
+	}
+
+	return &svgData, nil
+}
+
+// applyGraphAttrs sets the graph attributes of the options in the Config.
+func (d *SVGDiagram) applyGraphAttrs(graph *cgraph.Graph) {
+	if d.config.Direction != "" {
+		graph.SetRankDir(cgraph.RankDir(d.config.Direction))
+	}
+
+	if d.config.NodeSep > 0 {
+		graph.SetNodeSeparator(d.config.NodeSep)
+	}
+
+	if d.config.RankSep > 0 {
+		graph.SetRankSeparator(d.config.RankSep)
+	}
+}
+
+// NodeName returns the name of the node of a resource, which is also the title and text of the node in the SVG.
+func NodeName(res resources.Resource) string {
+	return fmt.Sprintf("%s%s%s%s%s",
+		res.ID(), ResourceInfoSeparator, res.Value(), ResourceInfoSeparator, res.ResourceType())
+}
+
+// EdgeTitle returns the title of the edge of a relationship in the SVG. Relationships between the same resources
+// share the same title, in the order they were added.
+func EdgeTitle(rel resources.Relationship) string {
+	return NodeName(rel.Source) + "->" + NodeName(rel.Target)
+}
+
+// subgraphFor returns the cluster of the container of a resource, creating the clusters of all its ancestors when
+// needed, or the graph itself when the resource is not inside a container. The depth is limited to stop on cyclic
+// hierarchies.
+func subgraphFor(
+	graph *cgraph.Graph, resCollection *resources.ResourceCollection, res resources.Resource,
+	subgraphs map[string]*cgraph.Graph, depth int,
+) *cgraph.Graph {
+	parent := resCollection.Parent(res)
+	if parent == nil || depth == 0 {
+		return graph
+	}
+
+	if sub, ok := subgraphs[parent.ID()]; ok {
+		return sub
+	}
+
+	sub := subgraphFor(graph, resCollection, parent, subgraphs, depth-1).SubGraph(ClusterPrefix+parent.ID(), 1)
+	subgraphs[parent.ID()] = sub
 
-	return &svgData
+	return sub
 }
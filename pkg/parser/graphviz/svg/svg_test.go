@@ -2,19 +2,23 @@ package drawioxml
 
 import (
 	"encoding/xml"
+	"errors"
 	"testing"
 
+	"github.com/diagram-code-generator/resources/pkg/parser/graphviz/dot"
 	"github.com/diagram-code-generator/resources/pkg/resources"
 	"github.com/stretchr/testify/require"
 )
 
 func TestSVGDiagram_Build(t *testing.T) {
 	type args struct {
+		config        *Config
 		resCollection *resources.ResourceCollection
 	}
 
 	lambda1 := resources.NewGenericResource("1", "lambda1", "lambda")
 	lambda2 := resources.NewGenericResource("2", "lambda2", "lambda")
+	vpc := resources.NewGenericResource("0", "vpc", "vpc")
 
 	tests := []struct {
 		name string
@@ -41,15 +45,124 @@ func TestSVGDiagram_Build(t *testing.T) {
 					Class: "graph",
 					Nodes: []Node{
 						{
+							Class: ClassNode,
 							Title: "1$$lambda1$$lambda",
-							Text:  Text{Content: "1$$lambda1$$lambda", X: "96.8597", Y: "-85.8"},
+							Text:  Text{Content: "1$$lambda1$$lambda", X: "70.2104", Y: "-91.8"},
+							Polygons: []Polygon{
+								{Points: "90.2705,-116 50.1503,-116 50.1503,-76 90.2705,-76 90.2705,-116"},
+							},
 						},
 						{
+							Class:    ClassNode,
+							Title:    "2$$lambda2$$lambda",
+							Text:     Text{Content: "2$$lambda2$$lambda", X: "70.2104", Y: "-15.8"},
+							Polygons: []Polygon{{Points: "90.2705,-40 50.1503,-40 50.1503,0 90.2705,0 90.2705,-40"}},
+						},
+						{
+							Class: ClassEdge,
+							Title: "1$$lambda1$$lambda->2$$lambda2$$lambda",
+							Polygons: []Polygon{
+								{Points: "73.7105,-50.1593 70.2104,-40.1593 66.7105,-50.1593 73.7105,-50.1593"},
+							},
+							Path: &Path{D: "M70.2104,-75.6334C70.2104,-67.8186 70.2104,-58.7253 70.2104,-50.183"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "containers are clusters",
+			args: args{
+				resCollection: &resources.ResourceCollection{
+					Resources: []resources.Resource{vpc, lambda1, lambda2},
+					Relationships: []resources.Relationship{
+						{Source: lambda1, Target: lambda2},
+						{Source: vpc, Target: lambda2},
+					},
+					Parents: map[string]string{"1": "0"},
+				},
+			},
+			want: &SVG{
+				XMLName: xml.Name{
+					Space: "http://www.w3.org/2000/svg",
+					Local: "svg",
+				},
+				G: G{
+					ID:    "graph0",
+					Class: "graph",
+					Nodes: []Node{
+						{
+							Class:    ClassCluster,
+							Title:    "cluster_0",
+							Polygons: []Polygon{{Points: "8,-68 8,-124 164,-124 164,-68 8,-68"}},
+						},
+						{
+							Class: ClassNode,
+							Title: "1$$lambda1$$lambda",
+							Text:  Text{Content: "1$$lambda1$$lambda", X: "86", Y: "-91.8"},
+							Polygons: []Polygon{
+								{Points: "106.0601,-116 65.9399,-116 65.9399,-76 106.0601,-76 106.0601,-116"},
+							},
+						},
+						{
+							Class:    ClassNode,
+							Title:    "2$$lambda2$$lambda",
+							Text:     Text{Content: "2$$lambda2$$lambda", X: "86", Y: "-15.8"},
+							Polygons: []Polygon{{Points: "106.0601,-40 65.9399,-40 65.9399,0 106.0601,0 106.0601,-40"}},
+						},
+						{
+							Class: ClassEdge,
+							Title: "1$$lambda1$$lambda->2$$lambda2$$lambda",
+							Polygons: []Polygon{
+								{Points: "89.5001,-50.1593 86,-40.1593 82.5001,-50.1593 89.5001,-50.1593"},
+							},
+							Path: &Path{D: "M86,-75.6334C86,-67.8186 86,-58.7253 86,-50.183"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "left to right with spacing",
+			args: args{
+				config: &Config{Direction: dot.DirectionLeftToRight, RankSep: 1},
+				resCollection: &resources.ResourceCollection{
+					Resources: []resources.Resource{lambda1, lambda2},
+					Relationships: []resources.Relationship{
+						{Source: lambda1, Target: lambda2},
+					},
+				},
+			},
+			want: &SVG{
+				XMLName: xml.Name{
+					Space: "http://www.w3.org/2000/svg",
+					Local: "svg",
+				},
+				G: G{
+					ID:    "graph0",
+					Class: "graph",
+					Nodes: []Node{
+						{
+							Class:    ClassNode,
+							Title:    "1$$lambda1$$lambda",
+							Text:     Text{Content: "1$$lambda1$$lambda", X: "70.2104", Y: "-15.8"},
+							Polygons: []Polygon{{Points: "90.2705,-40 50.1503,-40 50.1503,0 90.2705,0 90.2705,-40"}},
+						},
+						{
+							Class: ClassNode,
 							Title: "2$$lambda2$$lambda",
-							Text:  Text{Content: "2$$lambda2$$lambda", X: "96.8597", Y: "-13.8"},
+							Text:  Text{Content: "2$$lambda2$$lambda", X: "282.6312", Y: "-15.8"},
+							Polygons: []Polygon{
+								{Points: "302.6913,-40 262.5711,-40 262.5711,0 302.6913,0 302.6913,-40"},
+							},
 						},
 						{
+							Class: ClassEdge,
 							Title: "1$$lambda1$$lambda->2$$lambda2$$lambda",
+							Polygons: []Polygon{
+								{Points: "252.3251,-23.5001 262.3251,-20 252.325,-16.5001 252.3251,-23.5001"},
+							},
+							Path: &Path{D: "M90.5782,-20C128.0878,-20 208.2622,-20 252.2316,-20"},
 						},
 					},
 				},
@@ -59,11 +172,51 @@ func TestSVGDiagram_Build(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			d := NewSVGDiagram()
+			d := NewSVGDiagramWithConfig(tt.args.config)
 
-			got := d.Build(tt.args.resCollection)
+			got, err := d.Build(tt.args.resCollection)
 
+			require.NoError(t, err)
 			require.Equal(t, tt.want, got)
 		})
 	}
 }
+
+func TestSVGDiagram_Build_UnmarshalFails(t *testing.T) {
+	errDummy := errors.New("dummy error")
+
+	xmlUnmarshal = func([]byte, any) error { return errDummy }
+	defer func() { xmlUnmarshal = xml.Unmarshal }()
+
+	lambda := resources.NewGenericResource("1", "lambda1", "lambda")
+
+	got, err := NewSVGDiagram().Build(&resources.ResourceCollection{Resources: []resources.Resource{lambda}})
+
+	require.ErrorIs(t, err, errDummy)
+	require.Nil(t, got)
+}
+
+func TestSVGDiagram_Build_NilEndpoint(t *testing.T) {
+	lambda := resources.NewGenericResource("1", "lambda1", "lambda")
+
+	got, err := NewSVGDiagram().Build(&resources.ResourceCollection{
+		Resources:     []resources.Resource{lambda},
+		Relationships: []resources.Relationship{{Source: lambda}, {Target: lambda}},
+	})
+
+	require.NoError(t, err)
+
+	for _, node := range got.G.Nodes {
+		require.NotEqual(t, ClassEdge, node.Class)
+	}
+}
+
+func TestSVGDiagram_Build_UnsupportedEngine(t *testing.T) {
+	lambda := resources.NewGenericResource("1", "lambda1", "lambda")
+
+	got, err := NewSVGDiagramWithConfig(&Config{Engine: "unknown"}).Build(
+		&resources.ResourceCollection{Resources: []resources.Resource{lambda}})
+
+	require.ErrorIs(t, err, dot.ErrUnsupportedEngine)
+	require.Nil(t, got)
+}
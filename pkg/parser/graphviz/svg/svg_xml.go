@@ -13,9 +13,17 @@ type G struct {
 	Nodes []Node `xml:"g"`
 }
 
+// Node is a node, edge or cluster group of the SVG, as told by its Class.
 type Node struct {
+	Class string `xml:"class,attr"`
 	Title string `xml:"title"`
 	Text  Text   `xml:"text"`
+	// Polygons holds the shape of a node or cluster, or the arrowheads of an edge.
+	Polygons []Polygon `xml:"polygon"`
+	Ellipse  *Ellipse  `xml:"ellipse"`
+	Image    *Image    `xml:"image"`
+	// Path is the route of an edge.
+	Path *Path `xml:"path"`
 }
 
 type Text struct {
@@ -23,3 +31,26 @@ type Text struct {
 	X       string `xml:"x,attr"`
 	Y       string `xml:"y,attr"`
 }
+
+type Polygon struct {
+	Points string `xml:"points,attr"`
+}
+
+type Ellipse struct {
+	CX string `xml:"cx,attr"`
+	CY string `xml:"cy,attr"`
+	RX string `xml:"rx,attr"`
+	RY string `xml:"ry,attr"`
+}
+
+type Image struct {
+	Href   string `xml:"href,attr"`
+	X      string `xml:"x,attr"`
+	Y      string `xml:"y,attr"`
+	Width  string `xml:"width,attr"`
+	Height string `xml:"height,attr"`
+}
+
+type Path struct {
+	D string `xml:"d,attr"`
+}
@@ -1,12 +1,159 @@
 package resources
 
 import (
-	"fmt"
+	"io"
+	"os"
+	"sort"
+)
 
-	"github.com/diagram-code-generator/resources/internal/fmtcolor"
+// Fields reported by a ResourceChange or a RelationshipChange. Changed attributes are reported as FieldAttributePrefix
+// followed by the attribute key.
+const (
+	FieldValue           = "value"
+	FieldType            = "type"
+	FieldLabel           = "label"
+	FieldParent          = "parent"
+	FieldAttributePrefix = "attributes."
 )
 
+// ResourceChange describes a resource present in both collections whose fields changed.
+type ResourceChange struct {
+	Before Resource
+	After  Resource
+	// Fields lists the names of the changed fields, e.g. FieldValue, FieldType, FieldParent or "attributes.style".
+	Fields []string
+}
+
+// RelationshipChange describes a relationship present in both collections whose label or attributes changed.
+type RelationshipChange struct {
+	Before Relationship
+	After  Relationship
+	// Fields lists the names of the changed fields, e.g. FieldLabel or "attributes.style".
+	Fields []string
+}
+
+// DiffResult holds the differences between two resource collections, as found by Diff.
+type DiffResult struct {
+	AddedResourcesByType    map[string][]Resource
+	RemovedResourcesByType  map[string][]Resource
+	ModifiedResourcesByType map[string][]ResourceChange
+	AddedRelationships      []Relationship
+	RemovedRelationships    []Relationship
+	// ModifiedRelationships lists the relationships whose label or attributes changed, e.g. from "reads from" to
+	// "polls".
+	ModifiedRelationships []RelationshipChange
+}
+
+// Diff finds the differences between two resource collections. Resources are matched by ID first and then, for the
+// ones left, by value. A matched resource whose fields changed is reported as modified, and relationships between
+// matched resources are compared through their counterparts, so renaming a resource does not report its relationships
+// as removed and added again. Relationships are matched by their endpoints and kind, and the ones whose label or
+// attributes changed are reported as modified. Relationships with a nil endpoint are left out.
+func Diff(rc1, rc2 *ResourceCollection) DiffResult {
+	var (
+		addedResourcesByType, removedResourcesByType map[string][]Resource
+		modifiedResourcesByType                      map[string][]ResourceChange
+		addedRelationships, removedRelationships     []Relationship
+		modifiedRelationships                        []RelationshipChange
+	)
+
+	counterparts := matchResources(rc1.Resources, rc2.Resources)
+
+	// Find added, removed and modified resources.
+	matched := make(map[string]struct{}, len(counterparts))
+
+	removedResourcesByType = map[string][]Resource{}
+	modifiedResourcesByType = map[string][]ResourceChange{}
+
+	for _, res := range rc1.Resources {
+		other, exists := counterparts[res.ID()]
+		if !exists {
+			removedResourcesByType[res.ResourceType()] = append(removedResourcesByType[res.ResourceType()], res)
+			continue
+		}
+
+		matched[other.ID()] = struct{}{}
+
+		fields := changedFields(res, other)
+		if parentChanged(rc1, rc2, res, other, counterparts) {
+			fields = append(fields, FieldParent)
+		}
+
+		if len(fields) > 0 {
+			modifiedResourcesByType[other.ResourceType()] = append(modifiedResourcesByType[other.ResourceType()],
+				ResourceChange{Before: res, After: other, Fields: fields})
+		}
+	}
+
+	addedResourcesByType = map[string][]Resource{}
+
+	for _, res := range rc2.Resources {
+		if _, exists := matched[res.ID()]; !exists {
+			addedResourcesByType[res.ResourceType()] = append(addedResourcesByType[res.ResourceType()], res)
+		}
+	}
+
+	// Find added, removed and modified relationships.
+	rc1Key := func(res Resource) string {
+		if other, ok := counterparts[res.ID()]; ok {
+			return "id:" + other.ID()
+		}
+
+		return "res:" + res.Value() + "\x00" + res.ResourceType()
+	}
+
+	rc2Key := func(res Resource) string {
+		if _, ok := matched[res.ID()]; ok {
+			return "id:" + res.ID()
+		}
+
+		return "res:" + res.Value() + "\x00" + res.ResourceType()
+	}
+
+	rc1Relationships := relationshipKeys(rc1.Relationships, rc1Key)
+	rc2Relationships := relationshipKeys(rc2.Relationships, rc2Key)
+
+	for _, rel := range rc2.Relationships {
+		if rel.Source == nil || rel.Target == nil {
+			continue
+		}
+
+		if _, exists := rc1Relationships[relationshipKey(rel, rc2Key)]; !exists {
+			addedRelationships = append(addedRelationships, rel)
+		}
+	}
+
+	for _, rel := range rc1.Relationships {
+		if rel.Source == nil || rel.Target == nil {
+			continue
+		}
+
+		other, exists := rc2Relationships[relationshipKey(rel, rc1Key)]
+		if !exists {
+			removedRelationships = append(removedRelationships, rel)
+			continue
+		}
+
+		if fields := changedRelationshipFields(rel, other); len(fields) > 0 {
+			modifiedRelationships = append(modifiedRelationships,
+				RelationshipChange{Before: rel, After: other, Fields: fields})
+		}
+	}
+
+	return DiffResult{
+		AddedResourcesByType:    addedResourcesByType,
+		RemovedResourcesByType:  removedResourcesByType,
+		ModifiedResourcesByType: modifiedResourcesByType,
+		AddedRelationships:      addedRelationships,
+		RemovedRelationships:    removedRelationships,
+		ModifiedRelationships:   modifiedRelationships,
+	}
+}
+
 // FindDifferences finds the differences between two resource collections.
+//
+// Deprecated: FindDifferences matches resources by value only and does not report modified resources and
+// relationships. Use Diff instead.
 func FindDifferences(
 	rc1, rc2 *ResourceCollection,
 ) (addedResourcesByType, removedResourcesByType map[string][]Resource,
@@ -55,27 +202,31 @@ func FindDifferences(
 	return addedResourcesByType, removedResourcesByType, addedRelationships, removedRelationships
 }
 
-// PrintDiff prints the differences between two resource collections.
-func PrintDiff(rc1, rc2 *ResourceCollection, availableTypes []string) {
-	addedResourcesByType, removedResourcesByType, addedRelationships, removedRelationships := FindDifferences(rc1, rc2)
+// Help tests.
+var diffOutput io.Writer = os.Stdout
 
-	for _, k := range availableTypes {
-		if len(addedResourcesByType[k]) > 0 || len(removedResourcesByType[k]) > 0 {
-			fmtcolor.White.Printf("[%s]:\n", k)
-			printResources(addedResourcesByType[k], "+")
-			printResources(removedResourcesByType[k], "-")
-			fmt.Println()
-		}
-	}
+// PrintDiff prints the differences between two resource collections to the standard output with colors. Only the
+// resources of the types listed in availableTypes are printed, so with no types only the relationships are. Use
+// NewDiffReport with a DiffRenderer to write the differences somewhere else or in another format.
+func PrintDiff(rc1, rc2 *ResourceCollection, availableTypes []string) {
+	report := NewDiffReport(rc1, rc2, availableTypes)
+	// NewDiffReport reports every type when availableTypes is empty.
+	report.Types = availableTypes
 
-	fmtcolor.White.Println("[Relationships]:")
-	printRelationships(addedRelationships, "+")
-	printRelationships(removedRelationships, "-")
+	_ = report.Render(diffOutput, TerminalRenderer{})
 }
 
 // containsRelationship checks if a relationship is present in a slice of relationships.
 func containsRelationship(relationships []Relationship, rel Relationship) bool {
+	if rel.Source == nil || rel.Target == nil {
+		return false
+	}
+
 	for _, r := range relationships {
+		if r.Source == nil || r.Target == nil {
+			continue
+		}
+
 		if r.Source.Value() == rel.Source.Value() && r.Source.ResourceType() == rel.Source.ResourceType() &&
 			r.Target.Value() == rel.Target.Value() && r.Target.ResourceType() == rel.Target.ResourceType() {
 			return true
@@ -85,26 +236,138 @@ func containsRelationship(relationships []Relationship, rel Relationship) bool {
 	return false
 }
 
-// printResources prints the resources.
-func printResources(resources []Resource, simbol string) {
-	for _, res := range resources {
-		if simbol == "+" {
-			fmtcolor.Green.Printf("%s %s\n", simbol, res.Value())
-		} else {
-			fmtcolor.Red.Printf("%s %s\n", simbol, res.Value())
+// matchResources pairs the resources of two slices, first by ID and then by value. The result maps the ID of a
+// resource in resources1 to its counterpart in resources2.
+func matchResources(resources1, resources2 []Resource) map[string]Resource {
+	counterparts := map[string]Resource{}
+	matched := map[Resource]struct{}{}
+
+	byID := map[string]Resource{}
+	for _, res := range resources2 {
+		if _, exists := byID[res.ID()]; !exists {
+			byID[res.ID()] = res
+		}
+	}
+
+	for _, res := range resources1 {
+		if other, ok := byID[res.ID()]; ok {
+			if _, taken := matched[other]; !taken {
+				counterparts[res.ID()] = other
+				matched[other] = struct{}{}
+			}
 		}
 	}
+
+	for _, res := range resources1 {
+		if _, ok := counterparts[res.ID()]; ok {
+			continue
+		}
+
+		for _, other := range resources2 {
+			if _, taken := matched[other]; taken {
+				continue
+			}
+
+			if other.Value() == res.Value() {
+				counterparts[res.ID()] = other
+				matched[other] = struct{}{}
+
+				break
+			}
+		}
+	}
+
+	return counterparts
+}
+
+// changedFields returns the names of the fields that differ between two resources.
+func changedFields(before, after Resource) []string {
+	var fields []string
+
+	if before.Value() != after.Value() {
+		fields = append(fields, FieldValue)
+	}
+
+	if before.ResourceType() != after.ResourceType() {
+		fields = append(fields, FieldType)
+	}
+
+	for _, k := range changedAttributes(AttributesOf(before), AttributesOf(after)) {
+		fields = append(fields, FieldAttributePrefix+k)
+	}
+
+	return fields
 }
 
-// printRelationships prints the relationships.
-func printRelationships(relationships []Relationship, simbol string) {
+// parentChanged reports whether a resource was moved to another container. The parents are compared through their
+// counterparts, so renaming a container doesn't move its children.
+func parentChanged(rc1, rc2 *ResourceCollection, before, after Resource, counterparts map[string]Resource) bool {
+	parentBefore, hadParent := rc1.Parents[before.ID()]
+	parentAfter, hasParent := rc2.Parents[after.ID()]
+
+	if !hadParent || !hasParent {
+		return hadParent != hasParent
+	}
+
+	other, ok := counterparts[parentBefore]
+
+	return !ok || other.ID() != parentAfter
+}
+
+// changedAttributes returns the sorted keys of the attributes added, removed or changed between two attribute maps.
+func changedAttributes(before, after map[string]string) []string {
+	var keys []string
+
+	for k, v := range before {
+		if other, ok := after[k]; !ok || other != v {
+			keys = append(keys, k)
+		}
+	}
+
+	for k := range after {
+		if _, ok := before[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// changedRelationshipFields returns the names of the fields that differ between two relationships.
+func changedRelationshipFields(before, after Relationship) []string {
+	var fields []string
+
+	if before.Label != after.Label {
+		fields = append(fields, FieldLabel)
+	}
+
+	for _, k := range changedAttributes(before.Attributes, after.Attributes) {
+		fields = append(fields, FieldAttributePrefix+k)
+	}
+
+	return fields
+}
+
+// relationshipKeys indexes relationships by the keys of their endpoints and kind. When there are duplicates, the first
+// relationship is kept.
+func relationshipKeys(relationships []Relationship, key func(Resource) string) map[string]Relationship {
+	keys := make(map[string]Relationship, len(relationships))
 	for _, rel := range relationships {
-		if simbol == "+" {
-			fmtcolor.Green.Printf("%s Source: %s (%s)\n", simbol, rel.Source.Value(), rel.Source.ResourceType())
-			fmtcolor.Green.Printf("  Target: %s (%s)\n", rel.Target.Value(), rel.Target.ResourceType())
-		} else {
-			fmtcolor.Red.Printf("%s Source: %s (%s)\n", simbol, rel.Source.Value(), rel.Source.ResourceType())
-			fmtcolor.Red.Printf("  Target: %s (%s)\n", rel.Target.Value(), rel.Target.ResourceType())
+		if rel.Source == nil || rel.Target == nil {
+			continue
+		}
+
+		k := relationshipKey(rel, key)
+		if _, exists := keys[k]; !exists {
+			keys[k] = rel
 		}
 	}
+
+	return keys
+}
+
+func relationshipKey(rel Relationship, key func(Resource) string) string {
+	return key(rel.Source) + "###" + key(rel.Target) + "###" + rel.Kind
 }
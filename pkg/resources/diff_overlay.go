@@ -0,0 +1,197 @@
+package resources
+
+// DiffAttribute is the attribute holding the DiffStatus of the resources and relationships of a DiffCollection.
+const DiffAttribute = "diff"
+
+// DiffStatus tells how a resource or relationship of a DiffCollection changed.
+type DiffStatus string
+
+const (
+	DiffUnchanged DiffStatus = ""
+	DiffAdded     DiffStatus = "added"
+	DiffRemoved   DiffStatus = "removed"
+	DiffModified  DiffStatus = "modified"
+)
+
+// DiffCollection returns the union of two resource collections, so their differences can be drawn in a single
+// diagram. It holds the resources and copies of the relationships of rc2, followed by the ones removed from rc1. The
+// added, modified and removed resources are wrapped in a DiffResource, and the relationships have their DiffStatus in
+// the DiffAttribute attribute. The relationships of the removed ones are attached to the counterparts of their
+// endpoints in rc2, when there are any.
+func DiffCollection(rc1, rc2 *ResourceCollection) *ResourceCollection {
+	diff := Diff(rc1, rc2)
+
+	statuses := map[string]DiffStatus{}
+
+	for _, list := range diff.AddedResourcesByType {
+		for _, res := range list {
+			statuses[res.ID()] = DiffAdded
+		}
+	}
+
+	for _, changes := range diff.ModifiedResourcesByType {
+		for _, change := range changes {
+			statuses[change.After.ID()] = DiffModified
+		}
+	}
+
+	union := NewResourceCollection()
+	copies := make(map[string]Resource, len(rc2.Resources))
+
+	for _, res := range rc2.Resources {
+		copies[res.ID()] = withDiffStatus(res, statuses[res.ID()])
+		union.AddResource(copies[res.ID()])
+	}
+
+	for id, parentID := range rc2.Parents {
+		if union.Parents == nil {
+			union.Parents = map[string]string{}
+		}
+
+		union.Parents[id] = parentID
+	}
+
+	// The removed resources are looked up through their counterparts in rc2 first.
+	counterparts := matchResources(rc1.Resources, rc2.Resources)
+	removedCopies := map[string]Resource{}
+
+	for _, list := range diff.RemovedResourcesByType {
+		for _, res := range list {
+			removedCopies[res.ID()] = withDiffStatus(res, DiffRemoved)
+		}
+	}
+
+	lookup := func(res Resource) Resource {
+		if other, ok := counterparts[res.ID()]; ok {
+			return copies[other.ID()]
+		}
+
+		return removedCopies[res.ID()]
+	}
+
+	for _, res := range rc1.Resources {
+		removedCopy, ok := removedCopies[res.ID()]
+		if !ok {
+			continue
+		}
+
+		union.AddResource(removedCopy)
+
+		if parentID, ok := rc1.Parents[res.ID()]; ok {
+			if parent := rc1.ByID(parentID); parent != nil {
+				union.SetParent(removedCopy, lookup(parent))
+			}
+		}
+	}
+
+	relationshipStatuses := map[string]DiffStatus{}
+
+	for _, rel := range diff.AddedRelationships {
+		relationshipStatuses[diffRelationshipKey(rel)] = DiffAdded
+	}
+
+	for _, change := range diff.ModifiedRelationships {
+		relationshipStatuses[diffRelationshipKey(change.After)] = DiffModified
+	}
+
+	// Relationships whose endpoints are not in their collection are left out.
+	for _, rel := range rc2.Relationships {
+		if rel.Source == nil || rel.Target == nil || copies[rel.Source.ID()] == nil || copies[rel.Target.ID()] == nil {
+			continue
+		}
+
+		union.Relationships = append(union.Relationships, withRelationshipDiffStatus(rel,
+			copies[rel.Source.ID()], copies[rel.Target.ID()], relationshipStatuses[diffRelationshipKey(rel)]))
+	}
+
+	for _, rel := range diff.RemovedRelationships {
+		if rel.Source == nil || rel.Target == nil || lookup(rel.Source) == nil || lookup(rel.Target) == nil {
+			continue
+		}
+
+		union.Relationships = append(union.Relationships,
+			withRelationshipDiffStatus(rel, lookup(rel.Source), lookup(rel.Target), DiffRemoved))
+	}
+
+	return union
+}
+
+// DiffResource decorates a resource of a DiffCollection with its DiffStatus. Its attributes are the ones of the
+// decorated resource with the status in the DiffAttribute attribute.
+type DiffResource struct {
+	Resource
+	Status DiffStatus
+}
+
+// Attributes returns the attributes of the decorated resource with the status.
+func (r *DiffResource) Attributes() map[string]string {
+	attributes := map[string]string{}
+	for k, v := range AttributesOf(r.Resource) {
+		attributes[k] = v
+	}
+
+	if r.Status != DiffUnchanged {
+		attributes[DiffAttribute] = string(r.Status)
+	}
+
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	return attributes
+}
+
+// Unwrap returns the decorated resource.
+func (r *DiffResource) Unwrap() Resource {
+	return r.Resource
+}
+
+// DiffStatusOf returns the DiffStatus of a resource of a DiffCollection.
+func DiffStatusOf(res Resource) DiffStatus {
+	if r, ok := res.(*DiffResource); ok {
+		return r.Status
+	}
+
+	return DiffStatus(AttributesOf(res)[DiffAttribute])
+}
+
+// RelationshipDiffStatus returns the DiffStatus of a relationship of a DiffCollection.
+func RelationshipDiffStatus(rel Relationship) DiffStatus {
+	return DiffStatus(rel.Attributes[DiffAttribute])
+}
+
+// withDiffStatus returns the resource decorated with the status, or the resource itself when it is unchanged.
+func withDiffStatus(res Resource, status DiffStatus) Resource {
+	if status == DiffUnchanged {
+		return res
+	}
+
+	return &DiffResource{Resource: res, Status: status}
+}
+
+// withRelationshipDiffStatus returns a copy of the relationship between the given endpoints with the status in its
+// attributes.
+func withRelationshipDiffStatus(rel Relationship, source, target Resource, status DiffStatus) Relationship {
+	var attributes map[string]string
+
+	if len(rel.Attributes) > 0 || status != DiffUnchanged {
+		attributes = make(map[string]string, len(rel.Attributes)+1)
+		for k, v := range rel.Attributes {
+			attributes[k] = v
+		}
+	}
+
+	if status != DiffUnchanged {
+		attributes[DiffAttribute] = string(status)
+	}
+
+	return Relationship{Source: source, Target: target, Label: rel.Label, Kind: rel.Kind, Attributes: attributes}
+}
+
+func diffRelationshipKey(rel Relationship) string {
+	if rel.Source == nil || rel.Target == nil {
+		return ""
+	}
+
+	return rel.Source.ID() + "###" + rel.Target.ID() + "###" + rel.Kind + "###" + rel.Label
+}
@@ -0,0 +1,105 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffCollection(t *testing.T) {
+	vpc := NewGenericResource("10", "my-vpc", "vpc")
+	lambda1 := NewGenericResource("1", "receiver", lambdaType)
+	queue := NewGenericResource("2", "my-queue", sqsType)
+	stream := NewGenericResource("3", "my-stream", kinesisType)
+
+	lambda2 := NewGenericResourceWithAttributes("1", "receiver", lambdaType, map[string]string{"runtime": "go"})
+	topic := NewGenericResource("4", "my-topic", "sns")
+
+	rc1 := &ResourceCollection{
+		Resources: []Resource{vpc, lambda1, queue, stream},
+		Relationships: []Relationship{
+			{Source: lambda1, Target: queue, Label: "writes to"},
+			{Source: lambda1, Target: stream},
+		},
+		Parents: map[string]string{"1": "10", "3": "10"},
+	}
+
+	rc2 := &ResourceCollection{
+		Resources: []Resource{vpc, lambda2, queue, topic},
+		Relationships: []Relationship{
+			{Source: lambda2, Target: queue, Label: "sends to"},
+			{Source: lambda2, Target: topic, Kind: "async", Attributes: map[string]string{"style": "dashed"}},
+		},
+		Parents: map[string]string{"1": "10"},
+	}
+
+	got := DiffCollection(rc1, rc2)
+
+	wantVPC := vpc
+	wantLambda := &DiffResource{Resource: lambda2, Status: DiffModified}
+	wantQueue := queue
+	wantTopic := &DiffResource{Resource: topic, Status: DiffAdded}
+	wantStream := &DiffResource{Resource: stream, Status: DiffRemoved}
+
+	require.Equal(t, []Resource{wantVPC, wantLambda, wantQueue, wantTopic, wantStream}, got.Resources)
+	require.Equal(t, map[string]string{"1": "10", "3": "10"}, got.Parents)
+	require.Equal(t, []Relationship{
+		{
+			Source: wantLambda, Target: wantQueue, Label: "sends to",
+			Attributes: map[string]string{DiffAttribute: "modified"},
+		},
+		{
+			Source: wantLambda, Target: wantTopic, Kind: "async",
+			Attributes: map[string]string{"style": "dashed", DiffAttribute: "added"},
+		},
+		{Source: wantLambda, Target: wantStream, Attributes: map[string]string{DiffAttribute: "removed"}},
+	}, got.Relationships)
+
+	require.Equal(t, DiffModified, DiffStatusOf(got.Resources[1]))
+	require.Equal(t, DiffUnchanged, DiffStatusOf(got.Resources[2]))
+	require.Equal(t, DiffRemoved, RelationshipDiffStatus(got.Relationships[2]))
+
+	// The changed resources are decorated, keeping their attributes.
+	require.Equal(t, map[string]string{"runtime": "go", DiffAttribute: "modified"}, AttributesOf(got.Resources[1]))
+	require.Equal(t, map[string]string{DiffAttribute: "removed"}, AttributesOf(got.Resources[4]))
+	require.Same(t, lambda2, got.Resources[1].(*DiffResource).Unwrap())
+
+	// The collections are not changed.
+	require.Nil(t, AttributesOf(stream))
+	require.Equal(t, map[string]string{"style": "dashed"}, rc2.Relationships[1].Attributes)
+}
+
+func TestDiffCollection_NoChanges(t *testing.T) {
+	lambda := NewGenericResource("1", "receiver", lambdaType)
+	queue := NewGenericResource("2", "my-queue", sqsType)
+
+	rc := &ResourceCollection{
+		Resources:     []Resource{lambda, queue},
+		Relationships: []Relationship{{Source: lambda, Target: queue}},
+	}
+
+	got := DiffCollection(rc, rc)
+
+	require.Equal(t, rc.Resources, got.Resources)
+	require.Equal(t, rc.Relationships, got.Relationships)
+	require.Nil(t, got.Parents)
+}
+
+func TestDiffCollection_NilEndpoint(t *testing.T) {
+	lambda := NewGenericResource("1", "receiver", lambdaType)
+	queue := NewGenericResource("2", "my-queue", sqsType)
+
+	rc1 := &ResourceCollection{
+		Resources:     []Resource{lambda, queue},
+		Relationships: []Relationship{{Source: lambda}, {Source: lambda, Target: queue}},
+	}
+	rc2 := &ResourceCollection{
+		Resources:     []Resource{lambda, queue},
+		Relationships: []Relationship{{Target: queue}, {Source: lambda, Target: queue}},
+	}
+
+	got := DiffCollection(rc1, rc2)
+
+	require.Equal(t, rc2.Resources, got.Resources)
+	require.Equal(t, []Relationship{{Source: lambda, Target: queue}}, got.Relationships)
+}
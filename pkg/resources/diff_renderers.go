@@ -0,0 +1,351 @@
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fatih/color"
+
+	"github.com/diagram-code-generator/resources/internal/fmtcolor"
+)
+
+// nilEndpoint is written for the nil endpoints of the relationships of a report.
+const nilEndpoint = "<nil>"
+
+// TerminalRenderer renders a DiffReport as colored text, the same way PrintDiff does.
+type TerminalRenderer struct{}
+
+// TextRenderer renders a DiffReport as plain text, without any color escape sequences.
+type TextRenderer struct{}
+
+// MarkdownRenderer renders a DiffReport as Markdown, using diff code blocks so additions and removals are highlighted
+// by tools like GitHub pull request comments.
+type MarkdownRenderer struct{}
+
+// JSONRenderer renders a DiffReport as a JSON document.
+type JSONRenderer struct {
+	// Indent is used to indent the JSON output. When empty, the output is compact.
+	Indent string
+}
+
+// Render writes the report as colored text.
+func (TerminalRenderer) Render(w io.Writer, report *DiffReport) error {
+	return renderText(&diffWriter{w: w, colored: true}, report)
+}
+
+// Render writes the report as plain text.
+func (TextRenderer) Render(w io.Writer, report *DiffReport) error {
+	return renderText(&diffWriter{w: w}, report)
+}
+
+// Render writes the report as Markdown.
+func (MarkdownRenderer) Render(w io.Writer, report *DiffReport) error {
+	dw := &diffWriter{w: w}
+
+	for _, k := range report.Types {
+		if !report.hasTypeChanges(k) {
+			continue
+		}
+
+		dw.printf(nil, "#### %s\n\n```diff\n", k)
+
+		for _, res := range report.AddedResourcesByType[k] {
+			dw.printf(nil, "+ %s\n", res.Value())
+		}
+
+		for _, res := range report.RemovedResourcesByType[k] {
+			dw.printf(nil, "- %s\n", res.Value())
+		}
+
+		for _, change := range report.ModifiedResourcesByType[k] {
+			dw.printf(nil, "! %s\n", formatResourceChange(change))
+		}
+
+		dw.printf(nil, "```\n\n")
+	}
+
+	dw.printf(nil, "#### Relationships\n\n```diff\n")
+
+	for _, rel := range report.AddedRelationships {
+		dw.printf(nil, "+ %s\n", formatRelationship(rel))
+	}
+
+	for _, rel := range report.RemovedRelationships {
+		dw.printf(nil, "- %s\n", formatRelationship(rel))
+	}
+
+	for _, change := range report.ModifiedRelationships {
+		dw.printf(nil, "! %s\n", formatRelationshipChange(change))
+	}
+
+	dw.printf(nil, "```\n")
+
+	return dw.err
+}
+
+// Render writes the report as JSON.
+func (r JSONRenderer) Render(w io.Writer, report *DiffReport) error {
+	doc := jsonDiffReport{
+		Resources: []jsonTypeDiff{},
+		Relationships: jsonRelationshipDiff{
+			Added:    toJSONRelationships(report.AddedRelationships),
+			Removed:  toJSONRelationships(report.RemovedRelationships),
+			Modified: toJSONRelationshipChanges(report.ModifiedRelationships),
+		},
+	}
+
+	for _, k := range report.Types {
+		if !report.hasTypeChanges(k) {
+			continue
+		}
+
+		doc.Resources = append(doc.Resources, jsonTypeDiff{
+			Type:     k,
+			Added:    toJSONResources(report.AddedResourcesByType[k]),
+			Removed:  toJSONResources(report.RemovedResourcesByType[k]),
+			Modified: toJSONResourceChanges(report.ModifiedResourcesByType[k]),
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", r.Indent)
+
+	return enc.Encode(doc)
+}
+
+type jsonDiffReport struct {
+	Resources     []jsonTypeDiff       `json:"resources"`
+	Relationships jsonRelationshipDiff `json:"relationships"`
+}
+
+type jsonTypeDiff struct {
+	Type     string               `json:"type"`
+	Added    []jsonResource       `json:"added"`
+	Removed  []jsonResource       `json:"removed"`
+	Modified []jsonResourceChange `json:"modified"`
+}
+
+type jsonRelationshipDiff struct {
+	Added    []jsonRelationship       `json:"added"`
+	Removed  []jsonRelationship       `json:"removed"`
+	Modified []jsonRelationshipChange `json:"modified"`
+}
+
+type jsonResource struct {
+	ID    string `json:"id"`
+	Value string `json:"value"`
+	Type  string `json:"type"`
+}
+
+type jsonResourceChange struct {
+	Before jsonResource `json:"before"`
+	After  jsonResource `json:"after"`
+	Fields []string     `json:"fields"`
+}
+
+type jsonRelationship struct {
+	Source jsonResource `json:"source"`
+	Target jsonResource `json:"target"`
+	Label  string       `json:"label,omitempty"`
+	Kind   string       `json:"kind,omitempty"`
+}
+
+type jsonRelationshipChange struct {
+	Before jsonRelationship `json:"before"`
+	After  jsonRelationship `json:"after"`
+	Fields []string         `json:"fields"`
+}
+
+// diffWriter keeps the first error returned by the underlying writer so the renderers don't need to check every
+// single write.
+type diffWriter struct {
+	w       io.Writer
+	colored bool
+	err     error
+}
+
+func (dw *diffWriter) printf(c *color.Color, format string, a ...any) {
+	if dw.err != nil {
+		return
+	}
+
+	if dw.colored && c != nil {
+		_, dw.err = c.Fprintf(dw.w, format, a...)
+	} else {
+		_, dw.err = fmt.Fprintf(dw.w, format, a...)
+	}
+}
+
+func renderText(dw *diffWriter, report *DiffReport) error {
+	for _, k := range report.Types {
+		if report.hasTypeChanges(k) {
+			dw.printf(fmtcolor.White, "[%s]:\n", k)
+			writeResources(dw, report.AddedResourcesByType[k], "+")
+			writeResources(dw, report.RemovedResourcesByType[k], "-")
+			writeResourceChanges(dw, report.ModifiedResourcesByType[k])
+			dw.printf(nil, "\n")
+		}
+	}
+
+	dw.printf(fmtcolor.White, "[Relationships]:\n")
+	writeRelationships(dw, report.AddedRelationships, "+")
+	writeRelationships(dw, report.RemovedRelationships, "-")
+	writeRelationshipChanges(dw, report.ModifiedRelationships)
+
+	return dw.err
+}
+
+// writeResources writes the resources.
+func writeResources(dw *diffWriter, resources []Resource, simbol string) {
+	c := simbolColor(simbol)
+
+	for _, res := range resources {
+		dw.printf(c, "%s %s\n", simbol, res.Value())
+	}
+}
+
+// writeResourceChanges writes the modified resources.
+func writeResourceChanges(dw *diffWriter, changes []ResourceChange) {
+	for _, change := range changes {
+		dw.printf(fmtcolor.Yellow, "~ %s\n", formatResourceChange(change))
+	}
+}
+
+// writeRelationships writes the relationships.
+func writeRelationships(dw *diffWriter, relationships []Relationship, simbol string) {
+	c := simbolColor(simbol)
+
+	for _, rel := range relationships {
+		dw.printf(c, "%s Source: %s\n", simbol, formatEndpoint(rel.Source))
+		dw.printf(c, "  Target: %s\n", formatEndpoint(rel.Target))
+
+		if rel.Label != "" {
+			dw.printf(c, "  Label: %s\n", rel.Label)
+		}
+	}
+}
+
+// writeRelationshipChanges writes the modified relationships.
+func writeRelationshipChanges(dw *diffWriter, changes []RelationshipChange) {
+	c := fmtcolor.Yellow
+
+	for _, change := range changes {
+		rel := change.After
+
+		dw.printf(c, "~ Source: %s\n", formatEndpoint(rel.Source))
+		dw.printf(c, "  Target: %s\n", formatEndpoint(rel.Target))
+
+		if change.Before.Label != rel.Label {
+			dw.printf(c, "  Label: %s -> %s\n", change.Before.Label, rel.Label)
+		}
+
+		dw.printf(c, "  Changed: %s\n", strings.Join(change.Fields, ", "))
+	}
+}
+
+func simbolColor(simbol string) *color.Color {
+	if simbol == "+" {
+		return fmtcolor.Green
+	}
+
+	return fmtcolor.Red
+}
+
+// formatEndpoint returns the value and type of a relationship endpoint, or nilEndpoint when it is nil.
+func formatEndpoint(res Resource) string {
+	if res == nil {
+		return nilEndpoint
+	}
+
+	return fmt.Sprintf("%s (%s)", res.Value(), res.ResourceType())
+}
+
+func formatRelationship(rel Relationship) string {
+	text := formatEndpoint(rel.Source) + " -> " + formatEndpoint(rel.Target)
+
+	if rel.Label != "" {
+		text += fmt.Sprintf(" [%s]", rel.Label)
+	}
+
+	return text
+}
+
+func formatRelationshipChange(change RelationshipChange) string {
+	rel := change.After
+
+	text := formatEndpoint(rel.Source) + " -> " + formatEndpoint(rel.Target)
+
+	if change.Before.Label != rel.Label {
+		text += fmt.Sprintf(" [%s -> %s]", change.Before.Label, rel.Label)
+	}
+
+	return text + fmt.Sprintf(" (%s)", strings.Join(change.Fields, ", "))
+}
+
+func formatResourceChange(change ResourceChange) string {
+	return fmt.Sprintf("%s -> %s (%s)", change.Before.Value(), change.After.Value(), strings.Join(change.Fields, ", "))
+}
+
+// toJSONResource returns the JSON form of a resource, empty for a nil relationship endpoint.
+func toJSONResource(res Resource) jsonResource {
+	if res == nil {
+		return jsonResource{}
+	}
+
+	return jsonResource{ID: res.ID(), Value: res.Value(), Type: res.ResourceType()}
+}
+
+func toJSONResources(resources []Resource) []jsonResource {
+	result := make([]jsonResource, 0, len(resources))
+	for _, res := range resources {
+		result = append(result, toJSONResource(res))
+	}
+
+	return result
+}
+
+func toJSONResourceChanges(changes []ResourceChange) []jsonResourceChange {
+	result := make([]jsonResourceChange, 0, len(changes))
+	for _, change := range changes {
+		result = append(result, jsonResourceChange{
+			Before: toJSONResource(change.Before),
+			After:  toJSONResource(change.After),
+			Fields: change.Fields,
+		})
+	}
+
+	return result
+}
+
+func toJSONRelationship(rel Relationship) jsonRelationship {
+	return jsonRelationship{
+		Source: toJSONResource(rel.Source),
+		Target: toJSONResource(rel.Target),
+		Label:  rel.Label,
+		Kind:   rel.Kind,
+	}
+}
+
+func toJSONRelationships(relationships []Relationship) []jsonRelationship {
+	result := make([]jsonRelationship, 0, len(relationships))
+	for _, rel := range relationships {
+		result = append(result, toJSONRelationship(rel))
+	}
+
+	return result
+}
+
+func toJSONRelationshipChanges(changes []RelationshipChange) []jsonRelationshipChange {
+	result := make([]jsonRelationshipChange, 0, len(changes))
+	for _, change := range changes {
+		result = append(result, jsonRelationshipChange{
+			Before: toJSONRelationship(change.Before),
+			After:  toJSONRelationship(change.After),
+			Fields: change.Fields,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,98 @@
+package resources
+
+import (
+	"io"
+	"sort"
+)
+
+// DiffRenderer writes a DiffReport to an io.Writer in a specific output format.
+type DiffRenderer interface {
+	Render(w io.Writer, report *DiffReport) error
+}
+
+// DiffReport holds the differences between two resource collections, grouped by resource type, so they can be
+// rendered by any DiffRenderer.
+type DiffReport struct {
+	// Types lists the resource types to report, in the order they must be rendered.
+	Types []string
+
+	AddedResourcesByType    map[string][]Resource
+	RemovedResourcesByType  map[string][]Resource
+	ModifiedResourcesByType map[string][]ResourceChange
+	AddedRelationships      []Relationship
+	RemovedRelationships    []Relationship
+	ModifiedRelationships   []RelationshipChange
+}
+
+// NewDiffReport creates a DiffReport with the differences between two resource collections. Only the resource types
+// listed in availableTypes are reported; when availableTypes is empty, every type with differences is reported in
+// alphabetical order.
+func NewDiffReport(rc1, rc2 *ResourceCollection, availableTypes []string) *DiffReport {
+	diff := Diff(rc1, rc2)
+
+	report := &DiffReport{
+		Types:                   availableTypes,
+		AddedResourcesByType:    diff.AddedResourcesByType,
+		RemovedResourcesByType:  diff.RemovedResourcesByType,
+		ModifiedResourcesByType: diff.ModifiedResourcesByType,
+		AddedRelationships:      diff.AddedRelationships,
+		RemovedRelationships:    diff.RemovedRelationships,
+		ModifiedRelationships:   diff.ModifiedRelationships,
+	}
+
+	if len(report.Types) == 0 {
+		report.Types = report.typesWithDifferences()
+	}
+
+	return report
+}
+
+// HasChanges reports whether there is at least one difference to be rendered.
+func (r *DiffReport) HasChanges() bool {
+	for _, k := range r.Types {
+		if r.hasTypeChanges(k) {
+			return true
+		}
+	}
+
+	return len(r.AddedRelationships) > 0 || len(r.RemovedRelationships) > 0 || len(r.ModifiedRelationships) > 0
+}
+
+// Render writes the report to w using the given renderer.
+func (r *DiffReport) Render(w io.Writer, renderer DiffRenderer) error {
+	return renderer.Render(w, r)
+}
+
+func (r *DiffReport) hasTypeChanges(resourceType string) bool {
+	return len(r.AddedResourcesByType[resourceType]) > 0 || len(r.RemovedResourcesByType[resourceType]) > 0 ||
+		len(r.ModifiedResourcesByType[resourceType]) > 0
+}
+
+// typesWithDifferences returns the sorted resource types with at least one added, removed or modified resource.
+func (r *DiffReport) typesWithDifferences() []string {
+	seen := map[string]struct{}{}
+
+	for k := range r.AddedResourcesByType {
+		seen[k] = struct{}{}
+	}
+
+	for k := range r.RemovedResourcesByType {
+		seen[k] = struct{}{}
+	}
+
+	for k := range r.ModifiedResourcesByType {
+		seen[k] = struct{}{}
+	}
+
+	types := []string{}
+
+	for k := range seen {
+		if r.hasTypeChanges(k) {
+			types = append(types, k)
+		}
+	}
+
+	sort.Strings(types)
+
+	return types
+}
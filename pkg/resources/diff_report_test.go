@@ -0,0 +1,279 @@
+package resources
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type failingWriter struct{ err error }
+
+func (w *failingWriter) Write(_ []byte) (int, error) { return 0, w.err }
+
+func diffReportCollections() (rc1, rc2 *ResourceCollection) {
+	lambda := NewGenericResource("1", "myLambda", lambdaType)
+	queue := NewGenericResource("2", "my-queue", sqsType)
+	stream := NewGenericResource("3", "MyStream", kinesisType)
+
+	newLambda := NewGenericResource("4", "myLam", lambdaType)
+
+	rc1 = &ResourceCollection{
+		Resources:     []Resource{lambda, queue, stream},
+		Relationships: []Relationship{{Source: lambda, Target: queue}},
+	}
+	rc2 = &ResourceCollection{
+		Resources:     []Resource{newLambda, queue, stream},
+		Relationships: []Relationship{{Source: newLambda, Target: stream}},
+	}
+
+	return rc1, rc2
+}
+
+func TestNewDiffReport(t *testing.T) {
+	rc1, rc2 := diffReportCollections()
+
+	tests := []struct {
+		name           string
+		availableTypes []string
+		wantTypes      []string
+		wantChanges    bool
+	}{
+		{
+			name:           "with available types",
+			availableTypes: availableTypes,
+			wantTypes:      availableTypes,
+			wantChanges:    true,
+		},
+		{
+			name:           "without available types",
+			availableTypes: nil,
+			wantTypes:      []string{lambdaType},
+			wantChanges:    true,
+		},
+		{
+			name:           "with available types without differences",
+			availableTypes: []string{sqsType},
+			wantTypes:      []string{sqsType},
+			wantChanges:    true,
+		},
+	}
+
+	for i := range tests {
+		tc := tests[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			got := NewDiffReport(rc1, rc2, tc.availableTypes)
+
+			require.Equal(t, tc.wantTypes, got.Types)
+			require.Equal(t, tc.wantChanges, got.HasChanges())
+		})
+	}
+
+	require.False(t, NewDiffReport(rc1, rc1, nil).HasChanges())
+}
+
+func TestDiffReport_Render(t *testing.T) {
+	rc1, rc2 := diffReportCollections()
+
+	tests := []struct {
+		name     string
+		renderer DiffRenderer
+		want     string
+	}{
+		{
+			name:     "text",
+			renderer: TextRenderer{},
+			want: "[lambda]:\n" +
+				"+ myLam\n" +
+				"- myLambda\n" +
+				"\n" +
+				"[Relationships]:\n" +
+				"+ Source: myLam (lambda)\n" +
+				"  Target: MyStream (kinesis)\n" +
+				"- Source: myLambda (lambda)\n" +
+				"  Target: my-queue (sqs)\n",
+		},
+		{
+			name:     "markdown",
+			renderer: MarkdownRenderer{},
+			want: "#### lambda\n\n" +
+				"```diff\n" +
+				"+ myLam\n" +
+				"- myLambda\n" +
+				"```\n\n" +
+				"#### Relationships\n\n" +
+				"```diff\n" +
+				"+ myLam (lambda) -> MyStream (kinesis)\n" +
+				"- myLambda (lambda) -> my-queue (sqs)\n" +
+				"```\n",
+		},
+		{
+			name:     "json",
+			renderer: JSONRenderer{},
+			want: `{"resources":[{"type":"lambda","added":[{"id":"4","value":"myLam","type":"lambda"}],` +
+				`"removed":[{"id":"1","value":"myLambda","type":"lambda"}],"modified":[]}],` +
+				`"relationships":{"added":[{"source":{"id":"4","value":"myLam","type":"lambda"},` +
+				`"target":{"id":"3","value":"MyStream","type":"kinesis"}}],` +
+				`"removed":[{"source":{"id":"1","value":"myLambda","type":"lambda"},` +
+				`"target":{"id":"2","value":"my-queue","type":"sqs"}}],"modified":[]}}` + "\n",
+		},
+	}
+
+	for i := range tests {
+		tc := tests[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := NewDiffReport(rc1, rc2, availableTypes).Render(&buf, tc.renderer)
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, buf.String())
+		})
+	}
+}
+
+func TestDiffReport_RenderTerminal(t *testing.T) {
+	rc1, rc2 := diffReportCollections()
+
+	var buf bytes.Buffer
+
+	err := NewDiffReport(rc1, rc2, availableTypes).Render(&buf, TerminalRenderer{})
+
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "myLam")
+	require.Contains(t, buf.String(), "[Relationships]:")
+}
+
+func TestDiffReport_RenderNilEndpoint(t *testing.T) {
+	lambda := NewGenericResource("1", "myLambda", lambdaType)
+
+	report := &DiffReport{
+		AddedRelationships:   []Relationship{{Source: lambda}},
+		RemovedRelationships: []Relationship{{Target: lambda}},
+		ModifiedRelationships: []RelationshipChange{
+			{After: Relationship{Source: lambda}, Fields: []string{FieldLabel}},
+		},
+	}
+
+	for _, renderer := range []DiffRenderer{TerminalRenderer{}, TextRenderer{}, MarkdownRenderer{}, JSONRenderer{}} {
+		var buf bytes.Buffer
+
+		require.NoError(t, report.Render(&buf, renderer))
+		require.Contains(t, buf.String(), "myLambda")
+	}
+
+	var buf bytes.Buffer
+
+	require.NoError(t, report.Render(&buf, MarkdownRenderer{}))
+	require.Contains(t, buf.String(), "myLambda ("+lambdaType+") -> "+nilEndpoint)
+}
+
+func TestDiffReport_RenderWriterError(t *testing.T) {
+	errDummy := errors.New("dummy error")
+
+	rc1, rc2 := diffReportCollections()
+
+	for _, renderer := range []DiffRenderer{TerminalRenderer{}, TextRenderer{}, MarkdownRenderer{}, JSONRenderer{}} {
+		err := NewDiffReport(rc1, rc2, availableTypes).Render(&failingWriter{err: errDummy}, renderer)
+
+		require.ErrorIs(t, err, errDummy)
+	}
+}
+
+func TestDiffReport_RenderModified(t *testing.T) {
+	rc1 := &ResourceCollection{Resources: []Resource{NewGenericResource("1", "myReceiver", lambdaType)}}
+	rc2 := &ResourceCollection{Resources: []Resource{NewGenericResource("1", "myProcessor", lambdaType)}}
+
+	tests := []struct {
+		name     string
+		renderer DiffRenderer
+		want     string
+	}{
+		{
+			name:     "text",
+			renderer: TextRenderer{},
+			want:     "[lambda]:\n~ myReceiver -> myProcessor (value)\n\n[Relationships]:\n",
+		},
+		{
+			name:     "markdown",
+			renderer: MarkdownRenderer{},
+			want: "#### lambda\n\n```diff\n! myReceiver -> myProcessor (value)\n```\n\n" +
+				"#### Relationships\n\n```diff\n```\n",
+		},
+	}
+
+	for i := range tests {
+		tc := tests[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := NewDiffReport(rc1, rc2, nil).Render(&buf, tc.renderer)
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, buf.String())
+		})
+	}
+}
+
+func TestDiffReport_RenderModifiedRelationships(t *testing.T) {
+	lambda := NewGenericResource("1", "myLambda", lambdaType)
+	queue := NewGenericResource("2", "my-queue", sqsType)
+
+	rc1 := &ResourceCollection{
+		Resources:     []Resource{lambda, queue},
+		Relationships: []Relationship{{Source: lambda, Target: queue, Label: "reads from"}},
+	}
+	rc2 := &ResourceCollection{
+		Resources:     []Resource{lambda, queue},
+		Relationships: []Relationship{{Source: lambda, Target: queue, Label: "polls"}},
+	}
+
+	tests := []struct {
+		name     string
+		renderer DiffRenderer
+		want     string
+	}{
+		{
+			name:     "text",
+			renderer: TextRenderer{},
+			want: "[Relationships]:\n" +
+				"~ Source: myLambda (lambda)\n" +
+				"  Target: my-queue (sqs)\n" +
+				"  Label: reads from -> polls\n" +
+				"  Changed: label\n",
+		},
+		{
+			name:     "markdown",
+			renderer: MarkdownRenderer{},
+			want:     "#### Relationships\n\n```diff\n! myLambda (lambda) -> my-queue (sqs) [reads from -> polls] (label)\n```\n",
+		},
+		{
+			name:     "json",
+			renderer: JSONRenderer{},
+			want: `{"resources":[],"relationships":{"added":[],"removed":[],"modified":[{` +
+				`"before":{"source":{"id":"1","value":"myLambda","type":"lambda"},` +
+				`"target":{"id":"2","value":"my-queue","type":"sqs"},"label":"reads from"},` +
+				`"after":{"source":{"id":"1","value":"myLambda","type":"lambda"},` +
+				`"target":{"id":"2","value":"my-queue","type":"sqs"},"label":"polls"},` +
+				`"fields":["label"]}]}}` + "\n",
+		},
+	}
+
+	for i := range tests {
+		tc := tests[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			report := NewDiffReport(rc1, rc2, nil)
+
+			require.True(t, report.HasChanges())
+			require.NoError(t, report.Render(&buf, tc.renderer))
+			require.Equal(t, tc.want, buf.String())
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package resources
 
 import (
+	"bytes"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -10,6 +12,250 @@ var (
 	availableTypes = []string{kinesisType, lambdaType, sqsType}
 )
 
+func TestDiff(t *testing.T) {
+	type args struct {
+		rc1 *ResourceCollection
+		rc2 *ResourceCollection
+	}
+
+	lambda1Resource := NewGenericResource("1", "myReceiver", lambdaType)
+	sqs1Resource := NewGenericResource("2", "my-queue", sqsType)
+
+	lambda2Resource := NewGenericResource("1", "myProcessor", lambdaType)
+	sqs2Resource := NewGenericResource("2", "my-q", sqsType)
+
+	kinesisResource := NewGenericResource("3", "MyStream", kinesisType)
+	sqsAsKinesisResource := NewGenericResource("2", "my-queue", kinesisType)
+
+	lambdaWithAttrsResource := NewGenericResourceWithAttributes("1", "myReceiver", lambdaType,
+		map[string]string{"runtime": "go", "memory": "128", "handler": "main"})
+	lambdaWithOtherAttrsResource := NewGenericResourceWithAttributes("1", "myReceiver", lambdaType,
+		map[string]string{"runtime": "python", "timeout": "30", "handler": "main"})
+
+	vpc1Resource := NewGenericResource("10", "my-vpc", "vpc")
+	vpc2Resource := NewGenericResource("11", "other-vpc", "vpc")
+	renamedVPC1Resource := NewGenericResource("10", "main-vpc", "vpc")
+
+	lambda3Resource := NewGenericResource("4", "myReceiver", lambdaType)
+	sqs3Resource := NewGenericResource("5", "other-queue", sqsType)
+
+	tests := []struct {
+		name                        string
+		args                        args
+		wantAddedResourcesByType    map[string][]Resource
+		wantRemovedResourcesByType  map[string][]Resource
+		wantModifiedResourcesByType map[string][]ResourceChange
+		wantAddedRelationships      []Relationship
+		wantRemovedRelationships    []Relationship
+		wantModifiedRelationships   []RelationshipChange
+	}{
+		{
+			name: "happy path",
+			args: args{
+				rc1: &ResourceCollection{
+					Resources: []Resource{lambda1Resource, sqs1Resource},
+					Relationships: []Relationship{
+						{Source: lambda1Resource, Target: sqs1Resource},
+					},
+				},
+				rc2: &ResourceCollection{
+					Resources: []Resource{lambda2Resource, sqs2Resource, kinesisResource},
+					Relationships: []Relationship{
+						{Source: lambda2Resource, Target: sqs2Resource},
+						{Source: lambda2Resource, Target: kinesisResource},
+					},
+				},
+			},
+			wantAddedResourcesByType: map[string][]Resource{
+				kinesisType: {kinesisResource},
+			},
+			wantRemovedResourcesByType: map[string][]Resource{},
+			wantModifiedResourcesByType: map[string][]ResourceChange{
+				lambdaType: {{Before: lambda1Resource, After: lambda2Resource, Fields: []string{FieldValue}}},
+				sqsType:    {{Before: sqs1Resource, After: sqs2Resource, Fields: []string{FieldValue}}},
+			},
+			wantAddedRelationships: []Relationship{
+				{Source: lambda2Resource, Target: kinesisResource},
+			},
+			wantRemovedRelationships: nil,
+		},
+		{
+			name: "type changed on the same ID",
+			args: args{
+				rc1: &ResourceCollection{
+					Resources: []Resource{sqs1Resource},
+				},
+				rc2: &ResourceCollection{
+					Resources: []Resource{sqsAsKinesisResource},
+				},
+			},
+			wantAddedResourcesByType:   map[string][]Resource{},
+			wantRemovedResourcesByType: map[string][]Resource{},
+			wantModifiedResourcesByType: map[string][]ResourceChange{
+				kinesisType: {{Before: sqs1Resource, After: sqsAsKinesisResource, Fields: []string{FieldType}}},
+			},
+		},
+		{
+			name: "attributes changed on the same ID",
+			args: args{
+				rc1: &ResourceCollection{
+					Resources: []Resource{lambdaWithAttrsResource},
+				},
+				rc2: &ResourceCollection{
+					Resources: []Resource{lambdaWithOtherAttrsResource},
+				},
+			},
+			wantAddedResourcesByType:   map[string][]Resource{},
+			wantRemovedResourcesByType: map[string][]Resource{},
+			wantModifiedResourcesByType: map[string][]ResourceChange{
+				lambdaType: {{
+					Before: lambdaWithAttrsResource,
+					After:  lambdaWithOtherAttrsResource,
+					Fields: []string{"attributes.memory", "attributes.runtime", "attributes.timeout"},
+				}},
+			},
+		},
+		{
+			name: "resource moved to another container",
+			args: args{
+				rc1: &ResourceCollection{
+					Resources: []Resource{vpc1Resource, vpc2Resource, lambda1Resource, sqs1Resource},
+					Parents:   map[string]string{"1": "10", "2": "10"},
+				},
+				rc2: &ResourceCollection{
+					Resources: []Resource{renamedVPC1Resource, vpc2Resource, lambda1Resource, sqs1Resource},
+					Parents:   map[string]string{"1": "10", "2": "11"},
+				},
+			},
+			wantAddedResourcesByType:   map[string][]Resource{},
+			wantRemovedResourcesByType: map[string][]Resource{},
+			wantModifiedResourcesByType: map[string][]ResourceChange{
+				"vpc":   {{Before: vpc1Resource, After: renamedVPC1Resource, Fields: []string{FieldValue}}},
+				sqsType: {{Before: sqs1Resource, After: sqs1Resource, Fields: []string{FieldParent}}},
+			},
+		},
+		{
+			name: "resources with different IDs are matched by value",
+			args: args{
+				rc1: &ResourceCollection{
+					Resources: []Resource{lambda1Resource, sqs1Resource},
+					Relationships: []Relationship{
+						{Source: lambda1Resource, Target: sqs1Resource},
+					},
+				},
+				rc2: &ResourceCollection{
+					Resources: []Resource{lambda3Resource, sqs3Resource},
+					Relationships: []Relationship{
+						{Source: lambda3Resource, Target: sqs3Resource},
+					},
+				},
+			},
+			wantAddedResourcesByType: map[string][]Resource{
+				sqsType: {sqs3Resource},
+			},
+			wantRemovedResourcesByType: map[string][]Resource{
+				sqsType: {sqs1Resource},
+			},
+			wantModifiedResourcesByType: map[string][]ResourceChange{},
+			wantAddedRelationships: []Relationship{
+				{Source: lambda3Resource, Target: sqs3Resource},
+			},
+			wantRemovedRelationships: []Relationship{
+				{Source: lambda1Resource, Target: sqs1Resource},
+			},
+		},
+		{
+			name: "relationship label changed",
+			args: args{
+				rc1: &ResourceCollection{
+					Resources: []Resource{lambda1Resource, sqs1Resource},
+					Relationships: []Relationship{
+						{Source: lambda1Resource, Target: sqs1Resource, Label: "reads from", Kind: "read"},
+						{Source: lambda1Resource, Target: sqs1Resource, Label: "writes to", Kind: "write"},
+					},
+				},
+				rc2: &ResourceCollection{
+					Resources: []Resource{lambda1Resource, sqs1Resource},
+					Relationships: []Relationship{
+						{Source: lambda1Resource, Target: sqs1Resource, Label: "polls", Kind: "read"},
+						{Source: lambda1Resource, Target: sqs1Resource, Label: "sends to", Kind: "send"},
+					},
+				},
+			},
+			wantAddedResourcesByType:    map[string][]Resource{},
+			wantRemovedResourcesByType:  map[string][]Resource{},
+			wantModifiedResourcesByType: map[string][]ResourceChange{},
+			wantAddedRelationships: []Relationship{
+				{Source: lambda1Resource, Target: sqs1Resource, Label: "sends to", Kind: "send"},
+			},
+			wantRemovedRelationships: []Relationship{
+				{Source: lambda1Resource, Target: sqs1Resource, Label: "writes to", Kind: "write"},
+			},
+			wantModifiedRelationships: []RelationshipChange{{
+				Before: Relationship{Source: lambda1Resource, Target: sqs1Resource, Label: "reads from", Kind: "read"},
+				After:  Relationship{Source: lambda1Resource, Target: sqs1Resource, Label: "polls", Kind: "read"},
+				Fields: []string{FieldLabel},
+			}},
+		},
+		{
+			name: "empty",
+			args: args{
+				rc1: &ResourceCollection{},
+				rc2: &ResourceCollection{},
+			},
+			wantAddedResourcesByType:    map[string][]Resource{},
+			wantRemovedResourcesByType:  map[string][]Resource{},
+			wantModifiedResourcesByType: map[string][]ResourceChange{},
+			wantAddedRelationships:      nil,
+			wantRemovedRelationships:    nil,
+		},
+	}
+
+	for i := range tests {
+		tc := tests[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			got := Diff(tc.args.rc1, tc.args.rc2)
+
+			require.Equal(t, tc.wantAddedResourcesByType, got.AddedResourcesByType)
+			require.Equal(t, tc.wantRemovedResourcesByType, got.RemovedResourcesByType)
+			require.Equal(t, tc.wantModifiedResourcesByType, got.ModifiedResourcesByType)
+			require.Equal(t, tc.wantAddedRelationships, got.AddedRelationships)
+			require.Equal(t, tc.wantRemovedRelationships, got.RemovedRelationships)
+			require.Equal(t, tc.wantModifiedRelationships, got.ModifiedRelationships)
+		})
+	}
+}
+
+func TestDiff_NilEndpoint(t *testing.T) {
+	lambda := NewGenericResource("1", "myLambda", lambdaType)
+	queue := NewGenericResource("2", "my-queue", sqsType)
+
+	rc1 := &ResourceCollection{
+		Resources:     []Resource{lambda, queue},
+		Relationships: []Relationship{{Source: lambda}, {Source: lambda, Target: queue}},
+	}
+	rc2 := &ResourceCollection{
+		Resources:     []Resource{lambda, queue},
+		Relationships: []Relationship{{Target: queue}, {Source: lambda, Target: queue, Label: "sends to"}},
+	}
+
+	got := Diff(rc1, rc2)
+
+	require.Empty(t, got.AddedRelationships)
+	require.Empty(t, got.RemovedRelationships)
+	require.Equal(t, []RelationshipChange{{
+		Before: rc1.Relationships[1], After: rc2.Relationships[1], Fields: []string{FieldLabel},
+	}}, got.ModifiedRelationships)
+
+	added, removed, addedRels, removedRels := FindDifferences(rc1, rc2)
+
+	require.Empty(t, added)
+	require.Empty(t, removed)
+	require.Equal(t, []Relationship{rc2.Relationships[0]}, addedRels)
+	require.Equal(t, []Relationship{rc1.Relationships[0]}, removedRels)
+}
+
 func TestFindDifferences(t *testing.T) {
 	type args struct {
 		rc1 *ResourceCollection
@@ -61,6 +307,27 @@ func TestFindDifferences(t *testing.T) {
 				{Source: lambda1Resource, Target: sqs1Resource},
 			},
 		},
+		{
+			name: "relationship label changed",
+			args: args{
+				rc1: &ResourceCollection{
+					Resources: []Resource{lambda1Resource, sqs1Resource},
+					Relationships: []Relationship{
+						{Source: lambda1Resource, Target: sqs1Resource, Label: "reads from"},
+					},
+				},
+				rc2: &ResourceCollection{
+					Resources: []Resource{lambda1Resource, sqs1Resource},
+					Relationships: []Relationship{
+						{Source: lambda1Resource, Target: sqs1Resource, Label: "polls"},
+					},
+				},
+			},
+			wantAddedResourcesByType:   map[string][]Resource{},
+			wantRemovedResourcesByType: map[string][]Resource{},
+			wantAddedRelationships:     nil,
+			wantRemovedRelationships:   nil,
+		},
 		{
 			name: "empty",
 			args: args{
@@ -150,3 +417,18 @@ func TestPrintDiff(t *testing.T) {
 		})
 	}
 }
+
+func TestPrintDiff_NoTypes(t *testing.T) {
+	rc1, rc2 := diffReportCollections()
+
+	var buf bytes.Buffer
+
+	diffOutput = &buf
+	defer func() { diffOutput = os.Stdout }()
+
+	PrintDiff(rc1, rc2, nil)
+
+	require.NotContains(t, buf.String(), "+ myLam\n")
+	require.NotContains(t, buf.String(), "["+lambdaType+"]:")
+	require.Contains(t, buf.String(), "[Relationships]:")
+}
@@ -17,25 +17,44 @@ func (rc *ResourceCollection) Equal(other any) bool {
 func (rc *ResourceCollection) equal(other *ResourceCollection) bool {
 	if ok := rc.resourcesEqualValues(other.Resources); ok {
 		if ok := rc.relationshipsEqualValues(other.Relationships); ok {
-			return true
+			return rc.parentsEqualValues(other.Parents)
 		}
 	}
 
 	return false
 }
 
+func (rc *ResourceCollection) parentsEqualValues(otherParents map[string]string) bool {
+	if len(rc.Parents) != len(otherParents) {
+		return false
+	}
+
+	for childID, parentID := range rc.Parents {
+		if otherParentID, ok := otherParents[childID]; !ok || otherParentID != parentID {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (rc *ResourceCollection) resourcesEqualValues(otherResources []Resource) bool {
 	if len(rc.Resources) != len(otherResources) {
 		return false
 	}
 
-	rcIDs := make(map[string]struct{})
+	rcResources := make(map[string]Resource)
 	for _, resource := range rc.Resources {
-		rcIDs[resource.ID()] = struct{}{}
+		rcResources[resource.ID()] = resource
 	}
 
 	for _, resource := range otherResources {
-		if _, ok := rcIDs[resource.ID()]; !ok {
+		rcResource, ok := rcResources[resource.ID()]
+		if !ok {
+			return false
+		}
+
+		if len(changedAttributes(AttributesOf(rcResource), AttributesOf(resource))) > 0 {
 			return false
 		}
 	}
@@ -50,16 +69,18 @@ func (rc *ResourceCollection) relationshipsEqualValues(otherRelationships []Rela
 
 	rcRelationships := make(map[string]struct{})
 	for _, rel := range rc.Relationships {
-		key := fmt.Sprintf("%s_%s", rel.Source.ID(), rel.Target.ID())
-		rcRelationships[key] = struct{}{}
+		rcRelationships[equalRelationshipKey(rel)] = struct{}{}
 	}
 
 	for _, rel := range otherRelationships {
-		key := fmt.Sprintf("%s_%s", rel.Source.ID(), rel.Target.ID())
-		if _, ok := rcRelationships[key]; !ok {
+		if _, ok := rcRelationships[equalRelationshipKey(rel)]; !ok {
 			return false
 		}
 	}
 
 	return true
 }
+
+func equalRelationshipKey(rel Relationship) string {
+	return fmt.Sprintf("%s_%s_%s_%s", rel.Source.ID(), rel.Target.ID(), rel.Kind, rel.Label)
+}
@@ -10,6 +10,7 @@ func TestResourceCollection_Equal(t *testing.T) {
 	type fields struct {
 		Resources     []Resource
 		Relationships []Relationship
+		Parents       map[string]string
 	}
 
 	type args struct {
@@ -108,6 +109,22 @@ func TestResourceCollection_Equal(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "resource items with same IDs and different attributes",
+			fields: fields{
+				Resources:     resources1,
+				Relationships: relationships1,
+			},
+			args: args{
+				other: &ResourceCollection{
+					Resources: []Resource{
+						res3, res1, &GenericResource{id: "2", attributes: map[string]string{"style": "dashed=1"}},
+					},
+					Relationships: relationships2,
+				},
+			},
+			want: false,
+		},
 		{
 			name: "relationship items with same size and different values",
 			fields: fields{
@@ -125,6 +142,23 @@ func TestResourceCollection_Equal(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "relationship items with same endpoints and different labels",
+			fields: fields{
+				Resources:     resources1,
+				Relationships: relationships1,
+			},
+			args: args{
+				other: &ResourceCollection{
+					Resources: resources2,
+					Relationships: []Relationship{
+						{Source: res1, Target: res2, Label: "reads from"},
+						{Source: res2, Target: res3},
+					},
+				},
+			},
+			want: false,
+		},
 		{
 			name: "resource items are different",
 			fields: fields{
@@ -153,6 +187,53 @@ func TestResourceCollection_Equal(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "same parents",
+			fields: fields{
+				Resources:     resources1,
+				Relationships: relationships1,
+				Parents:       map[string]string{"2": "1", "3": "1"},
+			},
+			args: args{
+				other: &ResourceCollection{
+					Resources:     resources2,
+					Relationships: relationships2,
+					Parents:       map[string]string{"3": "1", "2": "1"},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "parents are different",
+			fields: fields{
+				Resources:     resources1,
+				Relationships: relationships1,
+				Parents:       map[string]string{"2": "1", "3": "1"},
+			},
+			args: args{
+				other: &ResourceCollection{
+					Resources:     resources2,
+					Relationships: relationships2,
+					Parents:       map[string]string{"2": "1", "3": "2"},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "parents with different sizes",
+			fields: fields{
+				Resources:     resources1,
+				Relationships: relationships1,
+				Parents:       map[string]string{"2": "1"},
+			},
+			args: args{
+				other: &ResourceCollection{
+					Resources:     resources2,
+					Relationships: relationships2,
+				},
+			},
+			want: false,
+		},
 		{
 			name: "comparing it to an instance that is not a ResourceCollection",
 			fields: fields{
@@ -173,6 +254,7 @@ func TestResourceCollection_Equal(t *testing.T) {
 			rc := &ResourceCollection{
 				Resources:     tc.fields.Resources,
 				Relationships: tc.fields.Relationships,
+				Parents:       tc.fields.Parents,
 			}
 
 			got := rc.Equal(tc.args.other)
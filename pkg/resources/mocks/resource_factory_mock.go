@@ -52,3 +52,40 @@ func (mr *MockResourceFactoryMockRecorder) CreateResource(id, value, style any)
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateResource", reflect.TypeOf((*MockResourceFactory)(nil).CreateResource), id, value, style)
 }
+
+// MockAttributedResourceFactory is a mock of AttributedResourceFactory interface.
+type MockAttributedResourceFactory struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttributedResourceFactoryMockRecorder
+}
+
+// MockAttributedResourceFactoryMockRecorder is the mock recorder for MockAttributedResourceFactory.
+type MockAttributedResourceFactoryMockRecorder struct {
+	mock *MockAttributedResourceFactory
+}
+
+// NewMockAttributedResourceFactory creates a new mock instance.
+func NewMockAttributedResourceFactory(ctrl *gomock.Controller) *MockAttributedResourceFactory {
+	mock := &MockAttributedResourceFactory{ctrl: ctrl}
+	mock.recorder = &MockAttributedResourceFactoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttributedResourceFactory) EXPECT() *MockAttributedResourceFactoryMockRecorder {
+	return m.recorder
+}
+
+// CreateResourceWithAttributes mocks base method.
+func (m *MockAttributedResourceFactory) CreateResourceWithAttributes(id, value, style string, attributes map[string]string) resources.Resource {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateResourceWithAttributes", id, value, style, attributes)
+	ret0, _ := ret[0].(resources.Resource)
+	return ret0
+}
+
+// CreateResourceWithAttributes indicates an expected call of CreateResourceWithAttributes.
+func (mr *MockAttributedResourceFactoryMockRecorder) CreateResourceWithAttributes(id, value, style, attributes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateResourceWithAttributes", reflect.TypeOf((*MockAttributedResourceFactory)(nil).CreateResourceWithAttributes), id, value, style, attributes)
+}
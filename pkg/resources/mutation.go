@@ -0,0 +1,162 @@
+package resources
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrResourceNotFound     = errors.New("resource not found")
+	ErrRelationshipNotFound = errors.New("relationship not found")
+	ErrDuplicateResourceID  = errors.New("duplicate resource ID")
+	ErrResourceNotRenamable = errors.New("resource does not implement ValueSetter")
+)
+
+// RemoveResource removes the resource with the same ID as the given one, together with all its relationships, which
+// are returned. The resources it contained are moved to its own container.
+func (rc *ResourceCollection) RemoveResource(resource Resource) ([]Relationship, error) {
+	i := rc.indexOf(resource.ID())
+	if i < 0 {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, resource.ID())
+	}
+
+	rc.Resources = append(rc.Resources[:i], rc.Resources[i+1:]...)
+
+	var removed []Relationship
+
+	relationships := make([]Relationship, 0, len(rc.Relationships))
+
+	for _, rel := range rc.Relationships {
+		if isEndpoint(rel.Source, resource.ID()) || isEndpoint(rel.Target, resource.ID()) {
+			removed = append(removed, rel)
+			continue
+		}
+
+		relationships = append(relationships, rel)
+	}
+
+	rc.Relationships = relationships
+
+	parentID, hasParent := rc.Parents[resource.ID()]
+	delete(rc.Parents, resource.ID())
+
+	for childID, id := range rc.Parents {
+		if id != resource.ID() {
+			continue
+		}
+
+		if hasParent {
+			rc.Parents[childID] = parentID
+		} else {
+			delete(rc.Parents, childID)
+		}
+	}
+
+	rc.refreshIndex()
+
+	return removed, nil
+}
+
+// RemoveRelationship removes the first relationship with the same source and target IDs, kind and label as the given
+// one.
+func (rc *ResourceCollection) RemoveRelationship(relationship Relationship) error {
+	for i, rel := range rc.Relationships {
+		if sameRelationship(rel, relationship) {
+			rc.Relationships = append(rc.Relationships[:i], rc.Relationships[i+1:]...)
+			rc.refreshIndex()
+
+			return nil
+		}
+	}
+
+	return ErrRelationshipNotFound
+}
+
+// ReplaceResource replaces the resource with the same ID as oldResource by newResource, keeping its position in the
+// collection. Relationships and the containment hierarchy are re-pointed to the new resource.
+func (rc *ResourceCollection) ReplaceResource(oldResource, newResource Resource) error {
+	i := rc.indexOf(oldResource.ID())
+	if i < 0 {
+		return fmt.Errorf("%w: %s", ErrResourceNotFound, oldResource.ID())
+	}
+
+	oldID, newID := oldResource.ID(), newResource.ID()
+
+	if newID != oldID && rc.indexOf(newID) >= 0 {
+		return fmt.Errorf("%w: %s", ErrDuplicateResourceID, newID)
+	}
+
+	rc.Resources[i] = newResource
+
+	for j := range rc.Relationships {
+		if isEndpoint(rc.Relationships[j].Source, oldID) {
+			rc.Relationships[j].Source = newResource
+		}
+
+		if isEndpoint(rc.Relationships[j].Target, oldID) {
+			rc.Relationships[j].Target = newResource
+		}
+	}
+
+	if newID != oldID {
+		if parentID, ok := rc.Parents[oldID]; ok {
+			delete(rc.Parents, oldID)
+			rc.Parents[newID] = parentID
+		}
+
+		for childID, parentID := range rc.Parents {
+			if parentID == oldID {
+				rc.Parents[childID] = newID
+			}
+		}
+	}
+
+	rc.refreshIndex()
+
+	return nil
+}
+
+// RenameResource changes the value of the resource with the same ID as the given one. The resource must implement
+// ValueSetter, as GenericResource does.
+func (rc *ResourceCollection) RenameResource(resource Resource, value string) error {
+	i := rc.indexOf(resource.ID())
+	if i < 0 {
+		return fmt.Errorf("%w: %s", ErrResourceNotFound, resource.ID())
+	}
+
+	setter, ok := rc.Resources[i].(ValueSetter)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrResourceNotRenamable, resource.ID())
+	}
+
+	setter.SetValue(value)
+
+	return nil
+}
+
+func (rc *ResourceCollection) indexOf(id string) int {
+	for i, res := range rc.Resources {
+		if res != nil && res.ID() == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func isEndpoint(res Resource, id string) bool {
+	return res != nil && res.ID() == id
+}
+
+func sameRelationship(rel1, rel2 Relationship) bool {
+	return sameEndpoint(rel1.Source, rel2.Source) && sameEndpoint(rel1.Target, rel2.Target) &&
+		rel1.Kind == rel2.Kind && rel1.Label == rel2.Label
+}
+
+func sameEndpoint(res1, res2 Resource) bool {
+	if res1 == nil || res2 == nil {
+		return res1 == nil && res2 == nil
+	}
+
+	return res1.ID() == res2.ID()
+}
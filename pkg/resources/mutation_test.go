@@ -0,0 +1,183 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type readOnlyResource struct{ id string }
+
+func (r readOnlyResource) ID() string           { return r.id }
+func (r readOnlyResource) Value() string        { return r.id }
+func (r readOnlyResource) ResourceType() string { return lambdaType }
+
+func mutationCollection() (rc *ResourceCollection, vpc, lambda, queue, stream Resource) {
+	vpc = NewGenericResource("1", "MyVPC", "vpc")
+	lambda = NewGenericResource("2", "MyLambda", lambdaType)
+	queue = NewGenericResource("3", "my-queue", sqsType)
+	stream = NewGenericResource("4", "MyStream", kinesisType)
+
+	rc = NewResourceCollection()
+	rc.AddResource(vpc)
+	rc.AddResource(lambda)
+	rc.AddResource(queue)
+	rc.AddResource(stream)
+	rc.AddRelationship(lambda, queue, WithLabel("writes to"))
+	rc.AddRelationship(stream, lambda)
+	rc.AddRelationship(queue, stream)
+	rc.SetParent(lambda, vpc)
+	rc.SetParent(queue, vpc)
+
+	return rc, vpc, lambda, queue, stream
+}
+
+func TestResourceCollection_RemoveResource(t *testing.T) {
+	t.Run("cascades to the relationships", func(t *testing.T) {
+		rc, vpc, lambda, queue, stream := mutationCollection()
+
+		// Builds the indexes so they must be refreshed after the removal.
+		require.Len(t, rc.Outgoing(stream), 1)
+
+		removed, err := rc.RemoveResource(lambda)
+
+		require.NoError(t, err)
+		require.Equal(t, []Relationship{
+			{Source: lambda, Target: queue, Label: "writes to"},
+			{Source: stream, Target: lambda},
+		}, removed)
+		require.Equal(t, []Resource{vpc, queue, stream}, rc.Resources)
+		require.Equal(t, []Relationship{{Source: queue, Target: stream}}, rc.Relationships)
+		require.Equal(t, map[string]string{"3": "1"}, rc.Parents)
+		require.Nil(t, rc.ByID("2"))
+		require.Empty(t, rc.Outgoing(stream))
+	})
+
+	t.Run("moves the children to the parent container", func(t *testing.T) {
+		rc, vpc, lambda, queue, _ := mutationCollection()
+
+		subnet := NewGenericResource("5", "MySubnet", "subnet")
+		rc.AddResource(subnet)
+		rc.SetParent(subnet, vpc)
+		rc.SetParent(lambda, subnet)
+
+		_, err := rc.RemoveResource(subnet)
+
+		require.NoError(t, err)
+		require.Equal(t, vpc, rc.Parent(lambda))
+
+		_, err = rc.RemoveResource(vpc)
+
+		require.NoError(t, err)
+		require.Nil(t, rc.Parent(lambda))
+		require.Nil(t, rc.Parent(queue))
+		require.Empty(t, rc.Parents)
+	})
+
+	t.Run("resource not found", func(t *testing.T) {
+		rc, _, _, _, _ := mutationCollection()
+
+		removed, err := rc.RemoveResource(NewGenericResource("9", "Unknown", lambdaType))
+
+		require.ErrorIs(t, err, ErrResourceNotFound)
+		require.Nil(t, removed)
+		require.Len(t, rc.Resources, 4)
+	})
+}
+
+func TestResourceCollection_RemoveRelationship(t *testing.T) {
+	rc, _, lambda, queue, stream := mutationCollection()
+
+	tests := []struct {
+		name         string
+		relationship Relationship
+		targetErr    error
+		want         []Relationship
+	}{
+		{
+			name:         "different label",
+			relationship: Relationship{Source: lambda, Target: queue, Label: "reads from"},
+			targetErr:    ErrRelationshipNotFound,
+			want: []Relationship{
+				{Source: lambda, Target: queue, Label: "writes to"},
+				{Source: stream, Target: lambda},
+				{Source: queue, Target: stream},
+			},
+		},
+		{
+			name:         "matched by endpoint IDs, kind and label",
+			relationship: Relationship{Source: NewGenericResource("2", "", ""), Target: queue, Label: "writes to"},
+			want: []Relationship{
+				{Source: stream, Target: lambda},
+				{Source: queue, Target: stream},
+			},
+		},
+		{
+			name:         "nil endpoints",
+			relationship: Relationship{},
+			targetErr:    ErrRelationshipNotFound,
+			want: []Relationship{
+				{Source: stream, Target: lambda},
+				{Source: queue, Target: stream},
+			},
+		},
+	}
+
+	for i := range tests {
+		tc := tests[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			err := rc.RemoveRelationship(tc.relationship)
+
+			require.ErrorIs(t, err, tc.targetErr)
+			require.Equal(t, tc.want, rc.Relationships)
+		})
+	}
+}
+
+func TestResourceCollection_ReplaceResource(t *testing.T) {
+	t.Run("re-points the relationships and the hierarchy", func(t *testing.T) {
+		rc, vpc, lambda, queue, stream := mutationCollection()
+
+		newVPC := NewGenericResource("10", "NewVPC", "vpc")
+		newLambda := NewGenericResource("20", "NewLambda", lambdaType)
+
+		require.NoError(t, rc.ReplaceResource(vpc, newVPC))
+		require.NoError(t, rc.ReplaceResource(lambda, newLambda))
+
+		require.Equal(t, []Resource{newVPC, newLambda, queue, stream}, rc.Resources)
+		require.Equal(t, []Relationship{
+			{Source: newLambda, Target: queue, Label: "writes to"},
+			{Source: stream, Target: newLambda},
+			{Source: queue, Target: stream},
+		}, rc.Relationships)
+		require.Equal(t, map[string]string{"20": "10", "3": "10"}, rc.Parents)
+		require.Equal(t, newLambda, rc.ByID("20"))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		rc, _, lambda, queue, _ := mutationCollection()
+
+		err := rc.ReplaceResource(NewGenericResource("9", "Unknown", lambdaType), lambda)
+		require.ErrorIs(t, err, ErrResourceNotFound)
+
+		err = rc.ReplaceResource(lambda, NewGenericResource(queue.ID(), "Other", lambdaType))
+		require.ErrorIs(t, err, ErrDuplicateResourceID)
+	})
+}
+
+func TestResourceCollection_RenameResource(t *testing.T) {
+	rc, _, lambda, _, _ := mutationCollection()
+
+	require.NoError(t, rc.RenameResource(lambda, "MyProcessor"))
+	require.Equal(t, "MyProcessor", lambda.Value())
+	require.Equal(t, "MyProcessor", rc.Relationships[0].Source.Value())
+
+	err := rc.RenameResource(NewGenericResource("9", "Unknown", lambdaType), "Other")
+	require.ErrorIs(t, err, ErrResourceNotFound)
+
+	rc.AddResource(readOnlyResource{id: "5"})
+
+	err = rc.RenameResource(readOnlyResource{id: "5"}, "Other")
+	require.ErrorIs(t, err, ErrResourceNotRenamable)
+}
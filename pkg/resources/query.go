@@ -0,0 +1,235 @@
+package resources
+
+import "sync"
+
+// resourceIndex holds the lookups used by the ResourceCollection queries.
+type resourceIndex struct {
+	byID     map[string]Resource
+	byType   map[string][]Resource
+	outgoing map[string][]Relationship
+	incoming map[string][]Relationship
+
+	// resources and relationships are the number of indexed entries, used to detect when the collection slices were
+	// appended to directly instead of through the collection methods, which change its generation. Elements replaced
+	// in place are not detected, see Reindex.
+	resources     int
+	relationships int
+}
+
+func newResourceIndex(rc *ResourceCollection) *resourceIndex {
+	idx := &resourceIndex{
+		byID:     make(map[string]Resource, len(rc.Resources)),
+		byType:   map[string][]Resource{},
+		outgoing: map[string][]Relationship{},
+		incoming: map[string][]Relationship{},
+	}
+
+	for _, res := range rc.Resources {
+		idx.addResource(res)
+	}
+
+	for _, rel := range rc.Relationships {
+		idx.addRelationship(rel)
+	}
+
+	return idx
+}
+
+func (idx *resourceIndex) addResource(res Resource) {
+	idx.resources++
+
+	if res == nil {
+		return
+	}
+
+	// The first resource with an ID wins, the same way a linear search would find it.
+	if _, ok := idx.byID[res.ID()]; !ok {
+		idx.byID[res.ID()] = res
+	}
+
+	idx.byType[res.ResourceType()] = append(idx.byType[res.ResourceType()], res)
+}
+
+func (idx *resourceIndex) addRelationship(rel Relationship) {
+	idx.relationships++
+
+	if rel.Source == nil || rel.Target == nil {
+		return
+	}
+
+	idx.outgoing[rel.Source.ID()] = append(idx.outgoing[rel.Source.ID()], rel)
+	idx.incoming[rel.Target.ID()] = append(idx.incoming[rel.Target.ID()], rel)
+}
+
+func (idx *resourceIndex) isStale(rc *ResourceCollection) bool {
+	return idx.resources != len(rc.Resources) || idx.relationships != len(rc.Relationships)
+}
+
+// indexStatesMu guards the allocation of the index states, so concurrent queries on a collection allocate only one.
+var indexStatesMu sync.Mutex
+
+// indexState holds the index of a collection. A copy of the collection struct shares the pointer, so the state is only
+// used by the collection that allocated it.
+type indexState struct {
+	// mu guards the index, so concurrent queries don't race to build it.
+	mu    sync.Mutex
+	owner *ResourceCollection
+	index *resourceIndex
+	// generation counts the changes made by the collection methods, and indexed is the generation the index was built
+	// for.
+	generation uint64
+	indexed    uint64
+}
+
+// state returns the index state of the collection, allocating it on the first query or after the collection was
+// copied.
+func (rc *ResourceCollection) state() *indexState {
+	indexStatesMu.Lock()
+	defer indexStatesMu.Unlock()
+
+	if rc.index == nil || rc.index.owner != rc {
+		rc.index = &indexState{owner: rc}
+	}
+
+	return rc.index
+}
+
+// ownState returns the index state of the collection, or nil when no query allocated it yet, so there is no index to
+// keep up to date.
+func (rc *ResourceCollection) ownState() *indexState {
+	if rc.index == nil || rc.index.owner != rc {
+		return nil
+	}
+
+	return rc.index
+}
+
+// lookup returns the index of the collection, building it again when it is missing or stale, e.g. the collection was
+// changed by its methods or its slices were appended to directly.
+func (rc *ResourceCollection) lookup() *resourceIndex {
+	state := rc.state()
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if state.index == nil || state.indexed != state.generation || state.index.isStale(rc) {
+		state.index = newResourceIndex(rc)
+		state.indexed = state.generation
+	}
+
+	return state.index
+}
+
+// updateIndex moves the collection to its next generation, applying an addition to the index when it is built and up
+// to date, so it doesn't need to be built again.
+func (rc *ResourceCollection) updateIndex(update func(idx *resourceIndex)) {
+	state := rc.ownState()
+	if state == nil {
+		return
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	upToDate := state.index != nil && state.indexed == state.generation && !state.index.isStale(rc)
+
+	state.generation++
+
+	if upToDate {
+		update(state.index)
+		state.indexed = state.generation
+	}
+}
+
+// refreshIndex moves the collection to its next generation after it was changed, so the next query builds the index
+// again.
+func (rc *ResourceCollection) refreshIndex() {
+	if state := rc.ownState(); state != nil {
+		state.mu.Lock()
+		defer state.mu.Unlock()
+
+		state.generation++
+	}
+}
+
+// Reindex rebuilds the indexes used by the queries. It is only needed after the Resources or Relationships slices are
+// changed in place, e.g. an element is replaced; changes made through the collection methods, and entries appended
+// directly, are picked up automatically.
+func (rc *ResourceCollection) Reindex() {
+	state := rc.state()
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	state.index = newResourceIndex(rc)
+	state.indexed = state.generation
+}
+
+// ByID returns the resource with the given ID, or nil when it is not in the collection.
+func (rc *ResourceCollection) ByID(id string) Resource {
+	return rc.lookup().byID[id]
+}
+
+// ByType returns the resources of the given type, in the collection order.
+func (rc *ResourceCollection) ByType(resourceType string) []Resource {
+	return clone(rc.lookup().byType[resourceType])
+}
+
+// Outgoing returns the relationships whose source is the given resource, in the collection order.
+func (rc *ResourceCollection) Outgoing(resource Resource) []Relationship {
+	return clone(rc.lookup().outgoing[resource.ID()])
+}
+
+// Incoming returns the relationships whose target is the given resource, in the collection order.
+func (rc *ResourceCollection) Incoming(resource Resource) []Relationship {
+	return clone(rc.lookup().incoming[resource.ID()])
+}
+
+// Neighbors returns the resources related to the given resource in either direction, without duplicates: the targets
+// of its outgoing relationships followed by the sources of its incoming relationships.
+func (rc *ResourceCollection) Neighbors(resource Resource) []Resource {
+	idx := rc.lookup()
+
+	var neighbors []Resource
+
+	seen := map[string]struct{}{}
+
+	add := func(res Resource) {
+		if _, ok := seen[res.ID()]; !ok {
+			seen[res.ID()] = struct{}{}
+			neighbors = append(neighbors, res)
+		}
+	}
+
+	for _, rel := range idx.outgoing[resource.ID()] {
+		add(rel.Target)
+	}
+
+	for _, rel := range idx.incoming[resource.ID()] {
+		add(rel.Source)
+	}
+
+	return neighbors
+}
+
+// Filter returns the resources matching the predicate, in the collection order.
+func (rc *ResourceCollection) Filter(predicate func(Resource) bool) []Resource {
+	var result []Resource
+
+	for _, res := range rc.Resources {
+		if res != nil && predicate(res) {
+			result = append(result, res)
+		}
+	}
+
+	return result
+}
+
+// clone returns a copy of the slice, so callers can't change the indexes, or nil when it is empty.
+func clone[T any](s []T) []T {
+	if len(s) == 0 {
+		return nil
+	}
+
+	return append([]T(nil), s...)
+}
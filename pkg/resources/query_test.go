@@ -0,0 +1,163 @@
+package resources
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceCollection_Queries(t *testing.T) {
+	lambda := NewGenericResource("1", "MyLambda", lambdaType)
+	queue := NewGenericResource("2", "my-queue", sqsType)
+	stream := NewGenericResource("3", "MyStream", kinesisType)
+	otherQueue := NewGenericResource("4", "other-queue", sqsType)
+
+	rc := NewResourceCollection()
+	rc.AddResource(lambda)
+	rc.AddResource(queue)
+	rc.AddRelationship(lambda, queue, WithLabel("writes to"))
+	rc.AddRelationship(queue, lambda, WithLabel("triggers"))
+	rc.AddRelationship(stream, lambda)
+
+	tests := []struct {
+		name string
+		got  any
+		want any
+	}{
+		{name: "by ID", got: rc.ByID("2"), want: Resource(queue)},
+		{name: "by unknown ID", got: rc.ByID("9"), want: Resource(nil)},
+		{name: "by type", got: rc.ByType(sqsType), want: []Resource{queue}},
+		{name: "by unknown type", got: rc.ByType("s3"), want: []Resource(nil)},
+		{
+			name: "outgoing",
+			got:  rc.Outgoing(lambda),
+			want: []Relationship{{Source: lambda, Target: queue, Label: "writes to"}},
+		},
+		{
+			name: "incoming",
+			got:  rc.Incoming(lambda),
+			want: []Relationship{
+				{Source: queue, Target: lambda, Label: "triggers"},
+				{Source: stream, Target: lambda},
+			},
+		},
+		{name: "neighbors", got: rc.Neighbors(lambda), want: []Resource{queue, stream}},
+		{name: "no neighbors", got: rc.Neighbors(otherQueue), want: []Resource(nil)},
+		{
+			name: "filter",
+			got:  rc.Filter(func(r Resource) bool { return strings.HasPrefix(r.Value(), "My") }),
+			want: []Resource{lambda},
+		},
+	}
+
+	for i := range tests {
+		tc := tests[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, tc.got)
+		})
+	}
+}
+
+func TestResourceCollection_QueriesKeepIndexesConsistent(t *testing.T) {
+	lambda := NewGenericResource("1", "MyLambda", lambdaType)
+	queue := NewGenericResource("2", "my-queue", sqsType)
+	otherQueue := NewGenericResource("3", "other-queue", sqsType)
+
+	rc := NewResourceCollection()
+	rc.AddResource(lambda)
+
+	require.Nil(t, rc.ByID("2"))
+
+	// Added through the collection methods after the indexes are built.
+	rc.AddResource(queue)
+	rc.AddRelationship(lambda, queue)
+
+	require.Equal(t, queue, rc.ByID("2"))
+	require.Equal(t, []Resource{queue}, rc.Neighbors(lambda))
+
+	// Appended directly to the slices.
+	rc.Resources = append(rc.Resources, otherQueue)
+	rc.Relationships = append(rc.Relationships, Relationship{Source: lambda, Target: otherQueue})
+
+	require.Equal(t, []Resource{queue, otherQueue}, rc.ByType(sqsType))
+	require.Equal(t, []Resource{queue, otherQueue}, rc.Neighbors(lambda))
+
+	// Replaced in place.
+	renamed := NewGenericResource("2", "renamed-queue", sqsType)
+	rc.Resources[1] = renamed
+	rc.Reindex()
+
+	require.Equal(t, renamed, rc.ByID("2"))
+
+	// The returned slices are copies.
+	rc.ByType(sqsType)[0] = nil
+
+	require.Equal(t, []Resource{renamed, otherQueue}, rc.ByType(sqsType))
+}
+
+func TestResourceCollection_QueriesAfterRemoveAndAdd(t *testing.T) {
+	lambda := NewGenericResource("1", "MyLambda", lambdaType)
+	queue := NewGenericResource("2", "my-queue", sqsType)
+	otherQueue := NewGenericResource("3", "other-queue", sqsType)
+
+	rc := &ResourceCollection{Resources: []Resource{lambda, queue}}
+
+	require.Equal(t, queue, rc.ByID("2"))
+
+	// The lengths are the same as when the index was built.
+	_, err := rc.RemoveResource(queue)
+	require.NoError(t, err)
+
+	rc.AddResource(otherQueue)
+
+	require.Nil(t, rc.ByID("2"))
+	require.Equal(t, []Resource{otherQueue}, rc.ByType(sqsType))
+}
+
+func TestResourceCollection_QueriesOnCopies(t *testing.T) {
+	lambda := NewGenericResource("1", "MyLambda", lambdaType)
+	queue := NewGenericResource("2", "my-queue", sqsType)
+	otherQueue := NewGenericResource("3", "other-queue", sqsType)
+
+	rc := &ResourceCollection{Resources: []Resource{lambda, queue}}
+
+	require.Equal(t, queue, rc.ByID("2"))
+
+	// The copy has as many resources as the collection, but doesn't share its index.
+	copied := *rc
+	copied.Resources = []Resource{lambda, otherQueue}
+
+	require.Nil(t, copied.ByID("2"))
+	require.Equal(t, otherQueue, copied.ByID("3"))
+	require.Equal(t, queue, rc.ByID("2"))
+	require.False(t, rc.Equal(copied))
+}
+
+func TestResourceCollection_ConcurrentQueries(t *testing.T) {
+	lambda := NewGenericResource("1", "MyLambda", lambdaType)
+	queue := NewGenericResource("2", "my-queue", sqsType)
+
+	// Built as a literal, so the first queries build the indexes.
+	rc := &ResourceCollection{
+		Resources:     []Resource{lambda, queue},
+		Relationships: []Relationship{{Source: lambda, Target: queue}},
+	}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			require.Equal(t, queue, rc.ByID("2"))
+			require.Equal(t, []Resource{queue}, rc.Neighbors(lambda))
+		}()
+	}
+
+	wg.Wait()
+}
@@ -4,3 +4,20 @@ package resources
 type ResourceFactory interface {
 	CreateResource(id, value, style string) Resource
 }
+
+// AttributedResourceFactory is an optional interface for factories that create resources with attributes, such as the
+// style and geometry of a diagram element.
+type AttributedResourceFactory interface {
+	CreateResourceWithAttributes(id, value, style string, attributes map[string]string) Resource
+}
+
+// CreateResource creates a resource with attributes through the factory. Only factories implementing
+// AttributedResourceFactory receive the attributes; the resources of other factories are returned as they create
+// them, without the attributes.
+func CreateResource(factory ResourceFactory, id, value, style string, attributes map[string]string) Resource {
+	if f, ok := factory.(AttributedResourceFactory); ok {
+		return f.CreateResourceWithAttributes(id, value, style, attributes)
+	}
+
+	return factory.CreateResource(id, value, style)
+}
@@ -8,33 +8,128 @@ type Resource interface {
 	ResourceType() string
 }
 
-// GenericResource represents a generic implementation of a resource, providing methods to retrieve the ID, value, and
-// resource type.
+// AttributedResource is an optional interface for resources that carry extra attributes besides the ID, value and
+// resource type, such as the style, custom properties or geometry of a diagram element.
+type AttributedResource interface {
+	Resource
+	Attributes() map[string]string
+}
+
+// ValueSetter is an optional interface for resources whose value can be changed after they are created, which is
+// required by ResourceCollection.RenameResource.
+type ValueSetter interface {
+	SetValue(value string)
+}
+
+// GenericResource represents a generic implementation of a resource, providing methods to retrieve the ID, value,
+// resource type and attributes.
 type GenericResource struct {
 	id           string
 	value        string
 	resourceType string
+	attributes   map[string]string
 }
 
 // NewGenericResource creates a new instance of GenericResource.
 func NewGenericResource(id, value string, resourceType string) *GenericResource {
 	return &GenericResource{id: id, value: value, resourceType: resourceType}
 }
+
+// NewGenericResourceWithAttributes creates a new instance of GenericResource with a copy of the given attributes.
+func NewGenericResourceWithAttributes(id, value, resourceType string, attributes map[string]string) *GenericResource {
+	r := NewGenericResource(id, value, resourceType)
+	for k, v := range attributes {
+		r.SetAttribute(k, v)
+	}
+
+	return r
+}
+
 func (r *GenericResource) ID() string           { return r.id }
 func (r *GenericResource) Value() string        { return r.value }
 func (r *GenericResource) ResourceType() string { return r.resourceType }
 
-// Relationship represents the relationship between two resources. It consists of a source and a target resource.
+// Attributes returns a copy of the resource attributes.
+func (r *GenericResource) Attributes() map[string]string {
+	if len(r.attributes) == 0 {
+		return nil
+	}
+
+	attributes := make(map[string]string, len(r.attributes))
+	for k, v := range r.attributes {
+		attributes[k] = v
+	}
+
+	return attributes
+}
+
+// SetValue sets the value of the resource.
+func (r *GenericResource) SetValue(value string) { r.value = value }
+
+// SetAttribute sets the value of an attribute.
+func (r *GenericResource) SetAttribute(key, value string) {
+	if r.attributes == nil {
+		r.attributes = map[string]string{}
+	}
+
+	r.attributes[key] = value
+}
+
+// AttributesOf returns the attributes of a resource, or nil when it doesn't implement AttributedResource.
+func AttributesOf(resource Resource) map[string]string {
+	if r, ok := resource.(AttributedResource); ok {
+		return r.Attributes()
+	}
+
+	return nil
+}
+
+// Relationship represents the relationship between two resources. It consists of a source and a target resource, and
+// optionally a label, a kind and attributes, so "reads from" and "writes to" relationships can be told apart.
 type Relationship struct {
-	Source Resource
-	Target Resource
+	Source     Resource
+	Target     Resource
+	Label      string
+	Kind       string
+	Attributes map[string]string
+}
+
+// RelationshipOption sets an optional field of a relationship.
+type RelationshipOption func(*Relationship)
+
+// WithLabel sets the label of a relationship.
+func WithLabel(label string) RelationshipOption {
+	return func(r *Relationship) { r.Label = label }
+}
+
+// WithKind sets the kind of a relationship.
+func WithKind(kind string) RelationshipOption {
+	return func(r *Relationship) { r.Kind = kind }
+}
+
+// WithAttribute sets an attribute of a relationship.
+func WithAttribute(key, value string) RelationshipOption {
+	return func(r *Relationship) {
+		if r.Attributes == nil {
+			r.Attributes = map[string]string{}
+		}
+
+		r.Attributes[key] = value
+	}
 }
 
 // ResourceCollection represents a collection of resources and their relationships. It includes slices to store
-// resources and relationships.
+// resources and relationships, and the containment hierarchy of the resources, e.g. subnets inside a VPC.
+//
+// Queries such as ByID can run concurrently, but not while the collection is being changed.
 type ResourceCollection struct {
 	Resources     []Resource
 	Relationships []Relationship
+	// Parents maps the ID of a resource to the ID of the resource containing it.
+	Parents map[string]string
+
+	// index speeds up the queries. It is allocated on the first query and kept up to date by the collection methods.
+	index *indexState
 }
 
 // NewResourceCollection creates a new ResourceCollection.
@@ -44,11 +139,74 @@ func NewResourceCollection() *ResourceCollection {
 
 // AddResource adds a resource to the collection.
 func (rc *ResourceCollection) AddResource(resource Resource) {
+	rc.updateIndex(func(idx *resourceIndex) { idx.addResource(resource) })
+
 	rc.Resources = append(rc.Resources, resource)
 }
 
 // AddRelationship adds a relationship to the collection.
-func (rc *ResourceCollection) AddRelationship(source, target Resource) {
+func (rc *ResourceCollection) AddRelationship(source, target Resource, options ...RelationshipOption) {
 	relationship := Relationship{Source: source, Target: target}
+	for _, option := range options {
+		option(&relationship)
+	}
+
+	rc.updateIndex(func(idx *resourceIndex) { idx.addRelationship(relationship) })
+
 	rc.Relationships = append(rc.Relationships, relationship)
 }
+
+// SetParent sets the resource containing the child resource. A nil parent removes the child from its container.
+func (rc *ResourceCollection) SetParent(child, parent Resource) {
+	if parent == nil {
+		delete(rc.Parents, child.ID())
+		return
+	}
+
+	if rc.Parents == nil {
+		rc.Parents = map[string]string{}
+	}
+
+	rc.Parents[child.ID()] = parent.ID()
+}
+
+// Parent returns the resource containing the given resource, or nil when it is not inside a container of the
+// collection.
+func (rc *ResourceCollection) Parent(child Resource) Resource {
+	parentID, ok := rc.Parents[child.ID()]
+	if !ok {
+		return nil
+	}
+
+	for _, res := range rc.Resources {
+		if res.ID() == parentID {
+			return res
+		}
+	}
+
+	return nil
+}
+
+// Children returns the resources directly contained by the given resource, in the collection order.
+func (rc *ResourceCollection) Children(parent Resource) []Resource {
+	var children []Resource
+
+	for _, res := range rc.Resources {
+		if parentID, ok := rc.Parents[res.ID()]; ok && parentID == parent.ID() {
+			children = append(children, res)
+		}
+	}
+
+	return children
+}
+
+// IsContainer reports whether the given resource contains other resources.
+func (rc *ResourceCollection) IsContainer(resource Resource) bool {
+	for _, parentID := range rc.Parents {
+		if parentID == resource.ID() {
+			return true
+		}
+	}
+
+	return false
+}
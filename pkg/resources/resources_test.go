@@ -65,8 +65,9 @@ func TestResourceCollection_AddRelationship(t *testing.T) {
 	}
 
 	type args struct {
-		source Resource
-		target Resource
+		source  Resource
+		target  Resource
+		options []RelationshipOption
 	}
 
 	tests := []struct {
@@ -90,6 +91,27 @@ func TestResourceCollection_AddRelationship(t *testing.T) {
 				Target: &GenericResource{id: "2", value: "MyQueue", resourceType: sqsType},
 			}},
 		},
+		{
+			name: "add labeled and typed relationship between a Lambda and SQS",
+			fields: fields{
+				Resources:     []Resource{},
+				Relationships: []Relationship{},
+			},
+			args: args{
+				source: NewGenericResource("1", "MyLambda", lambdaType),
+				target: NewGenericResource("2", "MyQueue", sqsType),
+				options: []RelationshipOption{
+					WithLabel("reads from"), WithKind("read"), WithAttribute("style", "dashed=1"),
+				},
+			},
+			want: []Relationship{{
+				Source:     &GenericResource{id: "1", value: "MyLambda", resourceType: lambdaType},
+				Target:     &GenericResource{id: "2", value: "MyQueue", resourceType: sqsType},
+				Label:      "reads from",
+				Kind:       "read",
+				Attributes: map[string]string{"style": "dashed=1"},
+			}},
+		},
 	}
 
 	for i := range tests {
@@ -100,9 +122,100 @@ func TestResourceCollection_AddRelationship(t *testing.T) {
 			rc.Resources = tc.fields.Resources
 			rc.Relationships = tc.fields.Relationships
 
-			rc.AddRelationship(tc.args.source, tc.args.target)
+			rc.AddRelationship(tc.args.source, tc.args.target, tc.args.options...)
 
 			require.Equal(t, tc.want, rc.Relationships)
 		})
 	}
 }
+
+func TestNewGenericResourceWithAttributes(t *testing.T) {
+	attributes := map[string]string{"style": "shape=lambda"}
+
+	got := NewGenericResourceWithAttributes("1", "MyLambda", lambdaType, attributes)
+
+	require.Equal(t, &GenericResource{
+		id: "1", value: "MyLambda", resourceType: lambdaType, attributes: map[string]string{"style": "shape=lambda"},
+	}, got)
+
+	// Changing the given map or the returned attributes must not change the resource.
+	attributes["style"] = "shape=sqs"
+	got.Attributes()["style"] = "shape=kinesis"
+
+	require.Equal(t, map[string]string{"style": "shape=lambda"}, got.Attributes())
+}
+
+func TestAttributesOf(t *testing.T) {
+	type plainResource struct{ Resource }
+
+	tests := []struct {
+		name     string
+		resource Resource
+		want     map[string]string
+	}{
+		{
+			name:     "resource with attributes",
+			resource: NewGenericResourceWithAttributes("1", "MyLambda", lambdaType, map[string]string{"x": "10"}),
+			want:     map[string]string{"x": "10"},
+		},
+		{
+			name:     "resource without attributes",
+			resource: NewGenericResource("1", "MyLambda", lambdaType),
+			want:     nil,
+		},
+		{
+			name:     "resource that does not implement AttributedResource",
+			resource: plainResource{NewGenericResource("1", "MyLambda", lambdaType)},
+			want:     nil,
+		},
+	}
+
+	for i := range tests {
+		tc := tests[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, AttributesOf(tc.resource))
+		})
+	}
+}
+
+func TestResourceCollection_Hierarchy(t *testing.T) {
+	vpc := NewGenericResource("1", "MyVPC", "vpc")
+	subnet := NewGenericResource("2", "MySubnet", "subnet")
+	lambda := NewGenericResource("3", "MyLambda", lambdaType)
+	queue := NewGenericResource("4", "MyQueue", sqsType)
+
+	rc := NewResourceCollection()
+	rc.AddResource(vpc)
+	rc.AddResource(subnet)
+	rc.AddResource(lambda)
+	rc.AddResource(queue)
+
+	rc.SetParent(subnet, vpc)
+	rc.SetParent(lambda, subnet)
+	rc.SetParent(queue, subnet)
+
+	require.Equal(t, map[string]string{"2": "1", "3": "2", "4": "2"}, rc.Parents)
+
+	require.Equal(t, vpc, rc.Parent(subnet))
+	require.Equal(t, subnet, rc.Parent(lambda))
+	require.Nil(t, rc.Parent(vpc))
+
+	require.Equal(t, []Resource{subnet}, rc.Children(vpc))
+	require.Equal(t, []Resource{lambda, queue}, rc.Children(subnet))
+	require.Nil(t, rc.Children(lambda))
+
+	require.True(t, rc.IsContainer(vpc))
+	require.True(t, rc.IsContainer(subnet))
+	require.False(t, rc.IsContainer(queue))
+
+	rc.SetParent(queue, nil)
+
+	require.Nil(t, rc.Parent(queue))
+	require.Equal(t, []Resource{lambda}, rc.Children(subnet))
+
+	// A parent that is not in the collection is not returned.
+	rc.SetParent(queue, NewGenericResource("5", "OtherVPC", "vpc"))
+
+	require.Nil(t, rc.Parent(queue))
+}
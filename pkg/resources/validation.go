@@ -0,0 +1,165 @@
+package resources
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrNilResource           = errors.New("nil resource")
+	ErrEmptyID               = errors.New("empty resource ID")
+	ErrEmptyValue            = errors.New("empty resource value")
+	ErrNilEndpoint           = errors.New("nil relationship endpoint")
+	ErrDanglingEndpoint      = errors.New("relationship endpoint not in the collection")
+	ErrSelfLoop              = errors.New("relationship from a resource to itself")
+	ErrDuplicateRelationship = errors.New("duplicate relationship")
+	ErrDanglingParent        = errors.New("parent not in the collection")
+)
+
+// ValidationIssue is a single problem found by ResourceCollection.Validate. Err is one of the sentinel errors of this
+// package, so issues can be checked with errors.Is.
+type ValidationIssue struct {
+	Err error
+	// Resource is the resource with the problem, if any.
+	Resource Resource
+	// Relationship is the relationship with the problem, if any, and RelationshipIndex its position in the
+	// collection.
+	Relationship      *Relationship
+	RelationshipIndex int
+}
+
+func (i *ValidationIssue) Error() string {
+	switch {
+	case i.Relationship != nil:
+		return fmt.Sprintf("relationship %d (%s -> %s): %v",
+			i.RelationshipIndex, describe(i.Relationship.Source), describe(i.Relationship.Target), i.Err)
+	case i.Resource != nil:
+		return fmt.Sprintf("resource %s: %v", describe(i.Resource), i.Err)
+	default:
+		return i.Err.Error()
+	}
+}
+
+func (i *ValidationIssue) Unwrap() error { return i.Err }
+
+// ValidationError holds every issue found by ResourceCollection.Validate.
+type ValidationError struct {
+	Issues []*ValidationIssue
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Issues))
+	for _, issue := range e.Issues {
+		messages = append(messages, issue.Error())
+	}
+
+	return fmt.Sprintf("invalid resource collection: %d issue(s):\n%s", len(e.Issues), strings.Join(messages, "\n"))
+}
+
+// Unwrap returns the issues, so errors.Is and errors.As match any of them.
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Issues))
+	for _, issue := range e.Issues {
+		errs = append(errs, issue)
+	}
+
+	return errs
+}
+
+// Validate checks that the collection is well-formed: resources are not nil and have unique IDs and non-empty values,
+// relationships point at resources of the collection, are not self-loops and are not duplicated, and parents are in
+// the collection. It returns a *ValidationError with every issue found, or nil.
+func (rc *ResourceCollection) Validate() error {
+	var issues []*ValidationIssue
+
+	ids := make(map[string]struct{}, len(rc.Resources))
+
+	for _, res := range rc.Resources {
+		if res == nil {
+			issues = append(issues, &ValidationIssue{Err: ErrNilResource})
+			continue
+		}
+
+		if res.ID() == "" {
+			issues = append(issues, &ValidationIssue{Err: ErrEmptyID, Resource: res})
+		} else if _, ok := ids[res.ID()]; ok {
+			issues = append(issues, &ValidationIssue{Err: ErrDuplicateResourceID, Resource: res})
+		}
+
+		if res.Value() == "" {
+			issues = append(issues, &ValidationIssue{Err: ErrEmptyValue, Resource: res})
+		}
+
+		ids[res.ID()] = struct{}{}
+	}
+
+	issues = append(issues, rc.validateRelationships(ids)...)
+
+	childIDs := make([]string, 0, len(rc.Parents))
+	for childID := range rc.Parents {
+		childIDs = append(childIDs, childID)
+	}
+
+	sort.Strings(childIDs)
+
+	for _, childID := range childIDs {
+		if _, ok := ids[rc.Parents[childID]]; !ok {
+			issues = append(issues, &ValidationIssue{Err: fmt.Errorf("%w: %s contained by %s",
+				ErrDanglingParent, childID, rc.Parents[childID])})
+		}
+	}
+
+	if len(issues) == 0 {
+		return nil
+	}
+
+	return &ValidationError{Issues: issues}
+}
+
+func (rc *ResourceCollection) validateRelationships(ids map[string]struct{}) []*ValidationIssue {
+	var issues []*ValidationIssue
+
+	seen := make(map[string]struct{}, len(rc.Relationships))
+
+	for i := range rc.Relationships {
+		rel := &rc.Relationships[i]
+
+		newIssue := func(err error) *ValidationIssue {
+			return &ValidationIssue{Err: err, Relationship: rel, RelationshipIndex: i}
+		}
+
+		if rel.Source == nil || rel.Target == nil {
+			issues = append(issues, newIssue(ErrNilEndpoint))
+			continue
+		}
+
+		for _, endpoint := range []Resource{rel.Source, rel.Target} {
+			if _, ok := ids[endpoint.ID()]; !ok {
+				issues = append(issues, newIssue(fmt.Errorf("%w: %s", ErrDanglingEndpoint, describe(endpoint))))
+			}
+		}
+
+		if rel.Source.ID() == rel.Target.ID() {
+			issues = append(issues, newIssue(ErrSelfLoop))
+		}
+
+		key := strings.Join([]string{rel.Source.ID(), rel.Target.ID(), rel.Kind, rel.Label}, "\x00")
+		if _, ok := seen[key]; ok {
+			issues = append(issues, newIssue(ErrDuplicateRelationship))
+		}
+
+		seen[key] = struct{}{}
+	}
+
+	return issues
+}
+
+func describe(res Resource) string {
+	if res == nil {
+		return "<nil>"
+	}
+
+	return fmt.Sprintf("%s (%s)", res.ID(), res.Value())
+}
@@ -0,0 +1,114 @@
+package resources
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceCollection_Validate(t *testing.T) {
+	lambda := NewGenericResource("1", "MyLambda", lambdaType)
+	queue := NewGenericResource("2", "my-queue", sqsType)
+	stream := NewGenericResource("3", "MyStream", kinesisType)
+
+	tests := []struct {
+		name       string
+		rc         *ResourceCollection
+		targetErrs []error
+		wantIssues int
+	}{
+		{
+			name: "valid collection",
+			rc: &ResourceCollection{
+				Resources: []Resource{lambda, queue},
+				Relationships: []Relationship{
+					{Source: lambda, Target: queue},
+					{Source: lambda, Target: queue, Kind: "read"},
+				},
+				Parents: map[string]string{"2": "1"},
+			},
+		},
+		{
+			name: "invalid resources",
+			rc: &ResourceCollection{
+				Resources: []Resource{
+					lambda,
+					nil,
+					NewGenericResource("1", "OtherLambda", lambdaType),
+					NewGenericResource("", "x", sqsType),
+					NewGenericResource("4", "", sqsType),
+				},
+			},
+			targetErrs: []error{ErrNilResource, ErrDuplicateResourceID, ErrEmptyID, ErrEmptyValue},
+			wantIssues: 4,
+		},
+		{
+			name: "invalid relationships",
+			rc: &ResourceCollection{
+				Resources: []Resource{lambda, queue},
+				Relationships: []Relationship{
+					{Source: lambda, Target: queue},
+					{Source: lambda, Target: nil},
+					{Source: lambda, Target: stream},
+					{Source: lambda, Target: lambda},
+					{Source: lambda, Target: queue},
+				},
+			},
+			targetErrs: []error{ErrNilEndpoint, ErrDanglingEndpoint, ErrSelfLoop, ErrDuplicateRelationship},
+			wantIssues: 4,
+		},
+		{
+			name: "dangling parent",
+			rc: &ResourceCollection{
+				Resources: []Resource{lambda},
+				Parents:   map[string]string{"1": "9"},
+			},
+			targetErrs: []error{ErrDanglingParent},
+			wantIssues: 1,
+		},
+	}
+
+	for i := range tests {
+		tc := tests[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.rc.Validate()
+
+			if tc.wantIssues == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			var validationErr *ValidationError
+
+			require.ErrorAs(t, err, &validationErr)
+			require.Len(t, validationErr.Issues, tc.wantIssues)
+
+			for _, targetErr := range tc.targetErrs {
+				require.ErrorIs(t, err, targetErr)
+			}
+		})
+	}
+}
+
+func TestValidationError_Error(t *testing.T) {
+	lambda := NewGenericResource("1", "MyLambda", lambdaType)
+	stream := NewGenericResource("3", "MyStream", kinesisType)
+
+	rc := &ResourceCollection{
+		Resources:     []Resource{lambda, nil},
+		Relationships: []Relationship{{Source: lambda, Target: stream}},
+	}
+
+	err := rc.Validate()
+
+	require.EqualError(t, err, "invalid resource collection: 2 issue(s):\n"+
+		"nil resource\n"+
+		"relationship 0 (1 (MyLambda) -> 3 (MyStream)): relationship endpoint not in the collection: 3 (MyStream)")
+
+	var issue *ValidationIssue
+
+	require.True(t, errors.As(err, &issue))
+	require.ErrorIs(t, issue, ErrNilResource)
+}
@@ -0,0 +1,165 @@
+// Package serializer encodes resource collections to a stable, versioned JSON or YAML document and decodes them back,
+// rebuilding the concrete resource types through a Registry.
+package serializer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/diagram-code-generator/resources/pkg/resources"
+)
+
+// CurrentVersion is the version of the document format written by this package.
+const CurrentVersion = 1
+
+var (
+	ErrUnsupportedVersion = errors.New("unsupported document version")
+	ErrUnknownResourceID  = errors.New("unknown resource ID")
+)
+
+// Document is the serialized form of a resource collection. Relationships and parents reference resources by ID.
+type Document struct {
+	Version       int                    `json:"version" yaml:"version"`
+	Resources     []ResourceDocument     `json:"resources" yaml:"resources"`
+	Relationships []RelationshipDocument `json:"relationships,omitempty" yaml:"relationships,omitempty"`
+	Parents       map[string]string      `json:"parents,omitempty" yaml:"parents,omitempty"`
+}
+
+// ResourceDocument is the serialized form of a resource.
+type ResourceDocument struct {
+	ID         string            `json:"id" yaml:"id"`
+	Value      string            `json:"value" yaml:"value"`
+	Type       string            `json:"type" yaml:"type"`
+	Attributes map[string]string `json:"attributes,omitempty" yaml:"attributes,omitempty"`
+}
+
+// RelationshipDocument is the serialized form of a relationship.
+type RelationshipDocument struct {
+	Source     string            `json:"source" yaml:"source"`
+	Target     string            `json:"target" yaml:"target"`
+	Label      string            `json:"label,omitempty" yaml:"label,omitempty"`
+	Kind       string            `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty" yaml:"attributes,omitempty"`
+}
+
+// ToDocument converts a resource collection to a Document. It fails on nil resources, duplicate resource IDs and
+// relationships with nil endpoints, which can't be referenced by ID.
+func ToDocument(rc *resources.ResourceCollection) (*Document, error) {
+	doc := &Document{Version: CurrentVersion, Resources: make([]ResourceDocument, 0, len(rc.Resources))}
+	ids := make(map[string]struct{}, len(rc.Resources))
+
+	for _, res := range rc.Resources {
+		if res == nil {
+			return nil, resources.ErrNilResource
+		}
+
+		if _, ok := ids[res.ID()]; ok {
+			return nil, fmt.Errorf("%w: %s", resources.ErrDuplicateResourceID, res.ID())
+		}
+
+		ids[res.ID()] = struct{}{}
+
+		doc.Resources = append(doc.Resources, ResourceDocument{
+			ID:         res.ID(),
+			Value:      res.Value(),
+			Type:       res.ResourceType(),
+			Attributes: resources.AttributesOf(res),
+		})
+	}
+
+	for _, rel := range rc.Relationships {
+		if rel.Source == nil || rel.Target == nil {
+			return nil, resources.ErrNilEndpoint
+		}
+
+		doc.Relationships = append(doc.Relationships, RelationshipDocument{
+			Source:     rel.Source.ID(),
+			Target:     rel.Target.ID(),
+			Label:      rel.Label,
+			Kind:       rel.Kind,
+			Attributes: copyAttributes(rel.Attributes),
+		})
+	}
+
+	if len(rc.Parents) > 0 {
+		doc.Parents = make(map[string]string, len(rc.Parents))
+		for child, parent := range rc.Parents {
+			doc.Parents[child] = parent
+		}
+	}
+
+	return doc, nil
+}
+
+// FromDocument rebuilds a resource collection from a Document. The resources are created by the registry, or by
+// NewRegistry when it is nil.
+func FromDocument(doc *Document, registry *Registry) (*resources.ResourceCollection, error) {
+	if doc.Version < 1 || doc.Version > CurrentVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, doc.Version)
+	}
+
+	if registry == nil {
+		registry = NewRegistry()
+	}
+
+	rc := resources.NewResourceCollection()
+	byID := make(map[string]resources.Resource, len(doc.Resources))
+
+	for _, r := range doc.Resources {
+		if _, ok := byID[r.ID]; ok {
+			return nil, fmt.Errorf("%w: %s", resources.ErrDuplicateResourceID, r.ID)
+		}
+
+		res := registry.Build(r.ID, r.Value, r.Type, r.Attributes)
+
+		byID[r.ID] = res
+		rc.AddResource(res)
+	}
+
+	for _, r := range doc.Relationships {
+		source, okSource := byID[r.Source]
+		if !okSource {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownResourceID, r.Source)
+		}
+
+		target, okTarget := byID[r.Target]
+		if !okTarget {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownResourceID, r.Target)
+		}
+
+		rc.Relationships = append(rc.Relationships, resources.Relationship{
+			Source:     source,
+			Target:     target,
+			Label:      r.Label,
+			Kind:       r.Kind,
+			Attributes: copyAttributes(r.Attributes),
+		})
+	}
+
+	for child, parent := range doc.Parents {
+		if _, ok := byID[child]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownResourceID, child)
+		}
+
+		if _, ok := byID[parent]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownResourceID, parent)
+		}
+
+		rc.SetParent(byID[child], byID[parent])
+	}
+
+	return rc, nil
+}
+
+func copyAttributes(attributes map[string]string) map[string]string {
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	result := make(map[string]string, len(attributes))
+	for k, v := range attributes {
+		result[k] = v
+	}
+
+	return result
+}
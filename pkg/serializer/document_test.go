@@ -0,0 +1,127 @@
+package serializer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/diagram-code-generator/resources/pkg/resources"
+)
+
+func TestToDocument(t *testing.T) {
+	lambda := resources.NewGenericResource("1", "MyLambda", "lambda")
+
+	tests := []struct {
+		name      string
+		rc        *resources.ResourceCollection
+		targetErr error
+	}{
+		{
+			name:      "nil resource",
+			rc:        &resources.ResourceCollection{Resources: []resources.Resource{nil}},
+			targetErr: resources.ErrNilResource,
+		},
+		{
+			name:      "duplicate ID",
+			rc:        &resources.ResourceCollection{Resources: []resources.Resource{lambda, lambda}},
+			targetErr: resources.ErrDuplicateResourceID,
+		},
+		{
+			name: "nil endpoint",
+			rc: &resources.ResourceCollection{
+				Resources:     []resources.Resource{lambda},
+				Relationships: []resources.Relationship{{Source: lambda}},
+			},
+			targetErr: resources.ErrNilEndpoint,
+		},
+	}
+
+	for i := range tests {
+		tc := tests[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ToDocument(tc.rc)
+
+			require.ErrorIs(t, err, tc.targetErr)
+		})
+	}
+}
+
+func TestFromDocument(t *testing.T) {
+	lambda := ResourceDocument{ID: "1", Value: "MyLambda", Type: "lambda"}
+
+	tests := []struct {
+		name      string
+		doc       *Document
+		targetErr error
+	}{
+		{
+			name:      "unsupported version",
+			doc:       &Document{Version: CurrentVersion + 1},
+			targetErr: ErrUnsupportedVersion,
+		},
+		{
+			name:      "missing version",
+			doc:       &Document{},
+			targetErr: ErrUnsupportedVersion,
+		},
+		{
+			name:      "duplicate ID",
+			doc:       &Document{Version: CurrentVersion, Resources: []ResourceDocument{lambda, lambda}},
+			targetErr: resources.ErrDuplicateResourceID,
+		},
+		{
+			name: "unknown relationship source",
+			doc: &Document{
+				Version:       CurrentVersion,
+				Resources:     []ResourceDocument{lambda},
+				Relationships: []RelationshipDocument{{Source: "9", Target: "1"}},
+			},
+			targetErr: ErrUnknownResourceID,
+		},
+		{
+			name: "unknown relationship target",
+			doc: &Document{
+				Version:       CurrentVersion,
+				Resources:     []ResourceDocument{lambda},
+				Relationships: []RelationshipDocument{{Source: "1", Target: "9"}},
+			},
+			targetErr: ErrUnknownResourceID,
+		},
+		{
+			name: "unknown child",
+			doc: &Document{
+				Version: CurrentVersion, Resources: []ResourceDocument{lambda}, Parents: map[string]string{"9": "1"},
+			},
+			targetErr: ErrUnknownResourceID,
+		},
+		{
+			name: "unknown parent",
+			doc: &Document{
+				Version: CurrentVersion, Resources: []ResourceDocument{lambda}, Parents: map[string]string{"1": "9"},
+			},
+			targetErr: ErrUnknownResourceID,
+		},
+	}
+
+	for i := range tests {
+		tc := tests[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := FromDocument(tc.doc, nil)
+
+			require.ErrorIs(t, err, tc.targetErr)
+			require.Nil(t, got)
+		})
+	}
+}
+
+func TestRegistry_Build(t *testing.T) {
+	registry := NewRegistry()
+
+	attributes := map[string]string{"fifo": "true"}
+
+	got := registry.Build("1", "my-queue", "sqs", attributes)
+
+	require.Equal(t, resources.NewGenericResourceWithAttributes("1", "my-queue", "sqs", attributes), got)
+}
@@ -0,0 +1,38 @@
+package serializer
+
+import "github.com/diagram-code-generator/resources/pkg/resources"
+
+// ResourceBuilder creates a resource from its serialized fields.
+type ResourceBuilder func(id, value string, attributes map[string]string) resources.Resource
+
+// Registry maps resource types to the builders of their concrete resources, the same way a resources.ResourceFactory
+// creates the resources of a diagram.
+type Registry struct {
+	builders map[string]ResourceBuilder
+	fallback func(id, value, resourceType string, attributes map[string]string) resources.Resource
+}
+
+// NewRegistry creates a Registry that builds a resources.GenericResource for the types without a registered builder.
+func NewRegistry() *Registry {
+	return &Registry{
+		builders: map[string]ResourceBuilder{},
+		fallback: func(id, value, resourceType string, attributes map[string]string) resources.Resource {
+			return resources.NewGenericResourceWithAttributes(id, value, resourceType, attributes)
+		},
+	}
+}
+
+// Register sets the builder of a resource type.
+func (r *Registry) Register(resourceType string, builder ResourceBuilder) *Registry {
+	r.builders[resourceType] = builder
+	return r
+}
+
+// Build creates the resource with the builder registered for its type, or a generic resource when there is none.
+func (r *Registry) Build(id, value, resourceType string, attributes map[string]string) resources.Resource {
+	if builder, ok := r.builders[resourceType]; ok {
+		return builder(id, value, copyAttributes(attributes))
+	}
+
+	return r.fallback(id, value, resourceType, attributes)
+}
@@ -0,0 +1,49 @@
+package serializer
+
+import (
+	"encoding/json"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/diagram-code-generator/resources/pkg/resources"
+)
+
+// MarshalJSON encodes a resource collection as an indented JSON document.
+func MarshalJSON(rc *resources.ResourceCollection) ([]byte, error) {
+	doc, err := ToDocument(rc)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// UnmarshalJSON decodes a JSON document into a resource collection, creating the resources with the registry.
+func UnmarshalJSON(data []byte, registry *Registry) (*resources.ResourceCollection, error) {
+	var doc Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	return FromDocument(&doc, registry)
+}
+
+// MarshalYAML encodes a resource collection as a YAML document.
+func MarshalYAML(rc *resources.ResourceCollection) ([]byte, error) {
+	doc, err := ToDocument(rc)
+	if err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(doc)
+}
+
+// UnmarshalYAML decodes a YAML document into a resource collection, creating the resources with the registry.
+func UnmarshalYAML(data []byte, registry *Registry) (*resources.ResourceCollection, error) {
+	var doc Document
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	return FromDocument(&doc, registry)
+}
@@ -0,0 +1,145 @@
+package serializer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/diagram-code-generator/resources/pkg/resources"
+)
+
+type lambdaResource struct {
+	*resources.GenericResource
+	runtime string
+}
+
+func newTestCollection() *resources.ResourceCollection {
+	vpc := resources.NewGenericResource("1", "MyVPC", "vpc")
+	lambda := &lambdaResource{
+		GenericResource: resources.NewGenericResourceWithAttributes(
+			"2", "MyLambda", "lambda", map[string]string{"runtime": "go1.x"}),
+		runtime: "go1.x",
+	}
+	queue := resources.NewGenericResource("3", "my-queue", "sqs")
+
+	rc := resources.NewResourceCollection()
+	rc.AddResource(vpc)
+	rc.AddResource(lambda)
+	rc.AddResource(queue)
+	rc.AddRelationship(lambda, queue, resources.WithLabel("writes to"), resources.WithKind("async"))
+	rc.SetParent(lambda, vpc)
+
+	return rc
+}
+
+func newTestRegistry() *Registry {
+	return NewRegistry().Register("lambda", func(id, value string, attributes map[string]string) resources.Resource {
+		return &lambdaResource{
+			GenericResource: resources.NewGenericResourceWithAttributes(id, value, "lambda", attributes),
+			runtime:         attributes["runtime"],
+		}
+	})
+}
+
+const wantJSON = `{
+  "version": 1,
+  "resources": [
+    {
+      "id": "1",
+      "value": "MyVPC",
+      "type": "vpc"
+    },
+    {
+      "id": "2",
+      "value": "MyLambda",
+      "type": "lambda",
+      "attributes": {
+        "runtime": "go1.x"
+      }
+    },
+    {
+      "id": "3",
+      "value": "my-queue",
+      "type": "sqs"
+    }
+  ],
+  "relationships": [
+    {
+      "source": "2",
+      "target": "3",
+      "label": "writes to",
+      "kind": "async"
+    }
+  ],
+  "parents": {
+    "2": "1"
+  }
+}`
+
+const wantYAML = `version: 1
+resources:
+    - id: "1"
+      value: MyVPC
+      type: vpc
+    - id: "2"
+      value: MyLambda
+      type: lambda
+      attributes:
+        runtime: go1.x
+    - id: "3"
+      value: my-queue
+      type: sqs
+relationships:
+    - source: "2"
+      target: "3"
+      label: writes to
+      kind: async
+parents:
+    "2": "1"
+`
+
+func TestJSON(t *testing.T) {
+	rc := newTestCollection()
+
+	data, err := MarshalJSON(rc)
+
+	require.NoError(t, err)
+	require.Equal(t, wantJSON, string(data))
+
+	got, err := UnmarshalJSON(data, newTestRegistry())
+
+	require.NoError(t, err)
+	require.Equal(t, rc, got)
+
+	_, err = UnmarshalJSON([]byte("{"), nil)
+
+	require.Error(t, err)
+}
+
+func TestYAML(t *testing.T) {
+	rc := newTestCollection()
+
+	data, err := MarshalYAML(rc)
+
+	require.NoError(t, err)
+	require.Equal(t, wantYAML, string(data))
+
+	got, err := UnmarshalYAML(data, newTestRegistry())
+
+	require.NoError(t, err)
+	require.Equal(t, rc, got)
+
+	_, err = UnmarshalYAML([]byte("version: ["), nil)
+
+	require.Error(t, err)
+}
+
+func TestMarshal_InvalidCollection(t *testing.T) {
+	rc := &resources.ResourceCollection{Resources: []resources.Resource{nil}}
+
+	_, err := MarshalJSON(rc)
+	require.ErrorIs(t, err, resources.ErrNilResource)
+
+	_, err = MarshalYAML(rc)
+	require.ErrorIs(t, err, resources.ErrNilResource)
+}
@@ -0,0 +1,11 @@
+package dottoresources
+
+// Config maps the DOT node attributes to resource types. The type found is passed to the ResourceFactory in the style,
+// as StyleType, e.g. "type=lambda;image=images/lambda.svg;shape=plaintext;".
+type Config struct {
+	// ResourceImageMap maps resource types to node images, the same map given to dot.Config to build the diagram. The
+	// type of a node is the one whose image is the node image.
+	ResourceImageMap map[string]string
+	// ShapeTypes maps node shapes to resource types. It is used for nodes without a mapped image.
+	ShapeTypes map[string]string
+}
@@ -0,0 +1,218 @@
+package dottoresources
+
+import (
+	"errors"
+	"maps"
+	"sort"
+	"strings"
+
+	"github.com/diagram-code-generator/resources/pkg/parser/graphviz/dotparse"
+	"github.com/diagram-code-generator/resources/pkg/resources"
+)
+
+var ErrInvalidDOT = errors.New("invalid DOT error")
+
+// StyleType is the style key with the resource type found from the node attributes by the Config maps.
+const StyleType = "type"
+
+// AttributeKind is the edge attribute read to set the kind of the relationship.
+const AttributeKind = "kind"
+
+// AttributeID is the node and cluster attribute read to set the resource ID, as the dot package writes it.
+const AttributeID = "id"
+
+// AttributeLegend marks the legend cluster and nodes written by the dot package, which are not resources.
+const AttributeLegend = "legend"
+
+type Transformer struct {
+	graph   *dotparse.Graph
+	factory resources.ResourceFactory
+	config  *Config
+}
+
+func NewTransformer(graph *dotparse.Graph, factory resources.ResourceFactory, config *Config) *Transformer {
+	if config == nil {
+		config = &Config{}
+	}
+
+	return &Transformer{graph: graph, factory: factory, config: config}
+}
+
+// Transform parses resources from the DOT graph. Nodes and clusters are passed to the factory with their ID (the
+// AttributeID attribute, or the DOT ID when there is none), their label (or ID, when they have no label) as the value
+// and their attributes as the style, serialized as "key=value;" entries sorted by key, with "%", ";" and "=" in the
+// keys and values percent-encoded, e.g. "label=a%3Bb;". The attributes, not encoded, are also passed to factories
+// implementing resources.AttributedResourceFactory; other factories get no attributes. Clusters created as resources
+// contain the resources of their nodes. The legend cluster and nodes, marked with AttributeLegend, are skipped.
+func (t *Transformer) Transform() (*resources.ResourceCollection, error) {
+	if t.graph == nil {
+		return nil, ErrInvalidDOT
+	}
+
+	resc := resources.NewResourceCollection()
+	clusters := map[*dotparse.Subgraph]resources.Resource{}
+
+	t.createClusters(resc, t.graph.Subgraphs, clusters)
+
+	resourcesMap := map[string]resources.Resource{}
+
+	for _, node := range t.graph.Nodes {
+		if isLegend(node.Attrs) {
+			continue
+		}
+
+		resource := t.createResource(node.ID, node.Attrs)
+		if resource == nil {
+			continue
+		}
+
+		resc.AddResource(resource)
+		resourcesMap[node.ID] = resource
+
+		if parent := closestCluster(node.Subgraph, clusters); parent != nil {
+			resc.SetParent(resource, parent)
+		}
+	}
+
+	clustersByID := make(map[string]resources.Resource, len(clusters))
+	for sub, resource := range clusters {
+		clustersByID[sub.ID] = resource
+	}
+
+	for _, edge := range t.graph.Edges {
+		source := endpointResource(resourcesMap[edge.From], edge.Attrs["ltail"], clustersByID)
+		target := endpointResource(resourcesMap[edge.To], edge.Attrs["lhead"], clustersByID)
+
+		if source != nil && target != nil {
+			resc.AddRelationship(source, target, relationshipOptions(edge)...)
+		}
+	}
+
+	return resc, nil
+}
+
+// createClusters creates the resources of the clusters, parents first, setting the container of each one.
+func (t *Transformer) createClusters(
+	resc *resources.ResourceCollection, subgraphs []*dotparse.Subgraph,
+	clusters map[*dotparse.Subgraph]resources.Resource,
+) {
+	for _, sub := range subgraphs {
+		if isLegend(sub.Attrs) {
+			continue
+		}
+
+		if sub.IsCluster() {
+			if resource := t.createResource(sub.ID, sub.Attrs); resource != nil {
+				resc.AddResource(resource)
+
+				if parent := closestCluster(sub.Parent, clusters); parent != nil {
+					resc.SetParent(resource, parent)
+				}
+
+				clusters[sub] = resource
+			}
+		}
+
+		t.createClusters(resc, sub.Subgraphs, clusters)
+	}
+}
+
+// createResource creates the resource of a node or cluster. Its ID is the AttributeID attribute, which is removed from
+// the style and attributes, or the DOT ID when there is none.
+func (t *Transformer) createResource(id string, attrs map[string]string) resources.Resource {
+	value := attrs["label"]
+	if value == "" {
+		value = id
+	}
+
+	if resourceID, ok := attrs[AttributeID]; ok {
+		id = resourceID
+
+		attrs = maps.Clone(attrs)
+		delete(attrs, AttributeID)
+	}
+
+	return resources.CreateResource(t.factory, id, value, t.style(attrs), attrs)
+}
+
+// styleEscaper percent-encodes the characters separating the style entries, and the percent sign itself.
+var styleEscaper = strings.NewReplacer("%", "%25", ";", "%3B", "=", "%3D")
+
+// style serializes the attributes, preceded by the resource type when the config maps them to one. The keys and values
+// are escaped by styleEscaper, so labels and URLs holding ";" or "=" keep the entries apart.
+func (t *Transformer) style(attrs map[string]string) string {
+	var sb strings.Builder
+
+	if resourceType := t.resourceType(attrs); resourceType != "" {
+		sb.WriteString(StyleType + "=" + styleEscaper.Replace(resourceType) + ";")
+	}
+
+	for _, k := range sortedKeys(attrs) {
+		sb.WriteString(styleEscaper.Replace(k) + "=" + styleEscaper.Replace(attrs[k]) + ";")
+	}
+
+	return sb.String()
+}
+
+func (t *Transformer) resourceType(attrs map[string]string) string {
+	if image, ok := attrs["image"]; ok {
+		for _, resourceType := range sortedKeys(t.config.ResourceImageMap) {
+			if t.config.ResourceImageMap[resourceType] == image {
+				return resourceType
+			}
+		}
+	}
+
+	return t.config.ShapeTypes[attrs["shape"]]
+}
+
+func isLegend(attrs map[string]string) bool {
+	return attrs[AttributeLegend] == "true"
+}
+
+// closestCluster returns the resource of the closest cluster containing the subgraph, including itself.
+func closestCluster(sub *dotparse.Subgraph, clusters map[*dotparse.Subgraph]resources.Resource) resources.Resource {
+	for ; sub != nil; sub = sub.Parent {
+		if resource, ok := clusters[sub]; ok {
+			return resource
+		}
+	}
+
+	return nil
+}
+
+// endpointResource returns the resource of the cluster an edge is clipped at, with lhead or ltail, or the resource of
+// the node otherwise.
+func endpointResource(
+	node resources.Resource, clusterID string, clustersByID map[string]resources.Resource,
+) resources.Resource {
+	if cluster, ok := clustersByID[clusterID]; ok {
+		return cluster
+	}
+
+	return node
+}
+
+// relationshipOptions returns the label, kind and attributes of the relationship created from an edge.
+func relationshipOptions(edge *dotparse.Edge) []resources.RelationshipOption {
+	options := []resources.RelationshipOption{
+		resources.WithLabel(edge.Attrs["label"]), resources.WithKind(edge.Attrs[AttributeKind]),
+	}
+
+	for _, k := range sortedKeys(edge.Attrs) {
+		options = append(options, resources.WithAttribute(k, edge.Attrs[k]))
+	}
+
+	return options
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
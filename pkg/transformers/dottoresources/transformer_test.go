@@ -0,0 +1,269 @@
+package dottoresources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/diagram-code-generator/resources/pkg/parser/graphviz/dot"
+	"github.com/diagram-code-generator/resources/pkg/parser/graphviz/dotparse"
+	"github.com/diagram-code-generator/resources/pkg/resources"
+	"github.com/diagram-code-generator/resources/pkg/resources/mocks"
+)
+
+func TestTransform(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	type args struct {
+		dotText string
+		config  *Config
+	}
+
+	config := &Config{
+		ResourceImageMap: map[string]string{"lambda": "images/lambda.svg", "sqs": "images/sqs.svg"},
+		ShapeTypes:       map[string]string{"cylinder": "database"},
+	}
+
+	tests := []struct {
+		name  string
+		args  args
+		setup func(*mocks.MockResourceFactory) *resources.ResourceCollection
+	}{
+		{
+			name: "nodes and edges",
+			args: args{
+				dotText: `digraph {
+					MyLambda [image="images/lambda.svg"]
+					queue [label="my-queue", image="images/sqs.svg"]
+					doc [shape=cylinder]
+					unknown
+					MyLambda -> queue [label="writes to", kind=async]
+					MyLambda -> doc
+					MyLambda -> unknown
+				}`,
+				config: config,
+			},
+			setup: func(mrf *mocks.MockResourceFactory) *resources.ResourceCollection {
+				lambda := resources.NewGenericResource("MyLambda", "MyLambda", "lambda")
+				queue := resources.NewGenericResource("queue", "my-queue", "sqs")
+				doc := resources.NewGenericResource("doc", "doc", "database")
+
+				mrf.EXPECT().CreateResource("MyLambda", "MyLambda", "type=lambda;image=images/lambda.svg;").
+					Return(lambda)
+				mrf.EXPECT().CreateResource("queue", "my-queue", "type=sqs;image=images/sqs.svg;label=my-queue;").
+					Return(queue)
+				mrf.EXPECT().CreateResource("doc", "doc", "type=database;shape=cylinder;").Return(doc)
+				mrf.EXPECT().CreateResource("unknown", "unknown", "").Return(nil)
+
+				return &resources.ResourceCollection{
+					Resources: []resources.Resource{lambda, queue, doc},
+					Relationships: []resources.Relationship{
+						{
+							Source: lambda, Target: queue, Label: "writes to", Kind: "async",
+							Attributes: map[string]string{"label": "writes to", "kind": "async"},
+						},
+						{Source: lambda, Target: doc},
+					},
+				}
+			},
+		},
+		{
+			name: "clusters are containers",
+			args: args{
+				dotText: `digraph {
+					compound=true
+					subgraph cluster_vpc {
+						label="my-vpc"
+						subgraph cluster_group { label="group" }
+						subgraph cluster_subnet {
+							label="private"
+							MyLambda
+						}
+						queue
+					}
+					stream -> MyLambda [lhead=cluster_vpc]
+				}`,
+			},
+			setup: func(mrf *mocks.MockResourceFactory) *resources.ResourceCollection {
+				vpc := resources.NewGenericResource("cluster_vpc", "my-vpc", "vpc")
+				subnet := resources.NewGenericResource("cluster_subnet", "private", "subnet")
+				lambda := resources.NewGenericResource("MyLambda", "MyLambda", "lambda")
+				queue := resources.NewGenericResource("queue", "queue", "sqs")
+				stream := resources.NewGenericResource("stream", "stream", "kinesis")
+
+				mrf.EXPECT().CreateResource("cluster_vpc", "my-vpc", "label=my-vpc;").Return(vpc)
+				mrf.EXPECT().CreateResource("cluster_group", "group", "label=group;").Return(nil)
+				mrf.EXPECT().CreateResource("cluster_subnet", "private", "label=private;").Return(subnet)
+				mrf.EXPECT().CreateResource("MyLambda", "MyLambda", "").Return(lambda)
+				mrf.EXPECT().CreateResource("queue", "queue", "").Return(queue)
+				mrf.EXPECT().CreateResource("stream", "stream", "").Return(stream)
+
+				return &resources.ResourceCollection{
+					Resources: []resources.Resource{vpc, subnet, lambda, queue, stream},
+					Relationships: []resources.Relationship{{
+						Source: stream, Target: vpc, Attributes: map[string]string{"lhead": "cluster_vpc"},
+					}},
+					Parents: map[string]string{
+						"cluster_subnet": "cluster_vpc", "MyLambda": "cluster_subnet", "queue": "cluster_vpc",
+					},
+				}
+			},
+		},
+	}
+
+	for i := range tests {
+		tc := tests[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			graph, err := dotparse.Parse([]byte(tc.args.dotText))
+			require.NoError(t, err)
+
+			factory := mocks.NewMockResourceFactory(ctrl)
+			want := tc.setup(factory)
+
+			got, err := NewTransformer(graph, factory, tc.args.config).Transform()
+
+			require.NoError(t, err)
+			require.Equal(t, want, got)
+		})
+	}
+}
+
+func TestTransform_InvalidDOT(t *testing.T) {
+	got, err := NewTransformer(nil, nil, nil).Transform()
+
+	require.ErrorIs(t, err, ErrInvalidDOT)
+	require.Nil(t, got)
+}
+
+func TestTransform_SetsAttributes(t *testing.T) {
+	graph, err := dotparse.Parse([]byte(`digraph { MyLambda [image="images/lambda.svg", tooltip="Handles orders"] }`))
+	require.NoError(t, err)
+
+	factory := attributedFactoryFunc(func(id, value, _ string, attributes map[string]string) resources.Resource {
+		return resources.NewGenericResourceWithAttributes(id, value, "lambda", attributes)
+	})
+
+	got, err := NewTransformer(graph, factory, nil).Transform()
+
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"image": "images/lambda.svg", "tooltip": "Handles orders"},
+		resources.AttributesOf(got.Resources[0]))
+
+	// Other factories get no attributes.
+	got, err = NewTransformer(graph, resourceFactoryFunc(func(id, value, _ string) resources.Resource {
+		return resources.NewGenericResource(id, value, "lambda")
+	}), nil).Transform()
+
+	require.NoError(t, err)
+	require.Nil(t, resources.AttributesOf(got.Resources[0]))
+}
+
+func TestTransform_EscapesStyle(t *testing.T) {
+	graph, err := dotparse.Parse([]byte(`digraph { MyLambda [label="a;b=c", URL="https://example.com/?q=1%2"] }`))
+	require.NoError(t, err)
+
+	var style string
+
+	factory := attributedFactoryFunc(func(id, value, s string, attributes map[string]string) resources.Resource {
+		style = s
+		return resources.NewGenericResourceWithAttributes(id, value, "lambda", attributes)
+	})
+
+	got, err := NewTransformer(graph, factory, nil).Transform()
+
+	require.NoError(t, err)
+	require.Equal(t, "URL=https://example.com/?q%3D1%252;label=a%3Bb%3Dc;", style)
+	require.Equal(t, "a;b=c", got.Resources[0].Value())
+	require.Equal(t, "a;b=c", resources.AttributesOf(got.Resources[0])["label"])
+}
+
+type resourceFactoryFunc func(id, value, style string) resources.Resource
+
+func (f resourceFactoryFunc) CreateResource(id, value, style string) resources.Resource {
+	return f(id, value, style)
+}
+
+type attributedFactoryFunc func(id, value, style string, attributes map[string]string) resources.Resource
+
+func (f attributedFactoryFunc) CreateResource(id, value, style string) resources.Resource {
+	return f(id, value, style, nil)
+}
+
+func (f attributedFactoryFunc) CreateResourceWithAttributes(
+	id, value, style string, attributes map[string]string,
+) resources.Resource {
+	return f(id, value, style, attributes)
+}
+
+func TestTransform_RoundTrip(t *testing.T) {
+	vpc := resources.NewGenericResource("vpc-1", "my-vpc", "vpc")
+	lambda := resources.NewGenericResource("lambda-1", "MyLambda", "lambda")
+	queue := resources.NewGenericResource("queue-1", "my-queue", "sqs")
+
+	resc := resources.NewResourceCollection()
+	resc.AddResource(vpc)
+	resc.AddResource(lambda)
+	resc.AddResource(queue)
+	resc.SetParent(lambda, vpc)
+	resc.AddRelationship(lambda, queue)
+	resc.AddRelationship(queue, vpc)
+
+	graph, err := dotparse.Parse([]byte(dot.NewDotDiagram(&dot.Config{ResourceIDs: true}).Build(resc)))
+	require.NoError(t, err)
+
+	factory := resourceFactoryFunc(func(id, value, _ string) resources.Resource {
+		return resources.NewGenericResource(id, value, "")
+	})
+
+	got, err := NewTransformer(graph, factory, nil).Transform()
+
+	require.NoError(t, err)
+
+	ids := make([]string, 0, len(got.Resources))
+	for _, res := range got.Resources {
+		ids = append(ids, res.ID())
+	}
+
+	require.ElementsMatch(t, []string{"vpc-1", "lambda-1", "queue-1"}, ids)
+	require.Equal(t, map[string]string{"lambda-1": "vpc-1"}, got.Parents)
+	require.Len(t, got.Relationships, 2)
+	require.Equal(t, "lambda-1", got.Relationships[0].Source.ID())
+	require.Equal(t, "queue-1", got.Relationships[0].Target.ID())
+	require.Equal(t, "queue-1", got.Relationships[1].Source.ID())
+	require.Equal(t, "vpc-1", got.Relationships[1].Target.ID())
+}
+
+func TestTransform_SkipsLegend(t *testing.T) {
+	lambda := resources.NewGenericResource("lambda-1", "MyLambda", "lambda")
+	queue := resources.NewGenericResource("queue-1", "my-queue", "sqs")
+
+	resc := resources.NewResourceCollection()
+	resc.AddResource(lambda)
+	resc.AddResource(queue)
+	resc.AddRelationship(lambda, queue)
+
+	config := &dot.Config{ResourceIDs: true, Legend: &dot.Legend{Edges: []dot.LegendEdge{{Description: "call"}}}}
+
+	graph, err := dotparse.Parse([]byte(dot.NewDotDiagram(config).Build(resc)))
+	require.NoError(t, err)
+
+	factory := resourceFactoryFunc(func(id, value, _ string) resources.Resource {
+		return resources.NewGenericResource(id, value, "")
+	})
+
+	got, err := NewTransformer(graph, factory, nil).Transform()
+
+	require.NoError(t, err)
+
+	ids := make([]string, 0, len(got.Resources))
+	for _, res := range got.Resources {
+		ids = append(ids, res.ID())
+	}
+
+	require.ElementsMatch(t, []string{"lambda-1", "queue-1"}, ids)
+	require.Empty(t, got.Parents)
+	require.Len(t, got.Relationships, 1)
+}
@@ -2,6 +2,8 @@ package drawiotoresources
 
 import (
 	"errors"
+	"strconv"
+	"strings"
 
 	drawioxml "github.com/joselitofilho/drawio-parser-go/pkg/parser/xml"
 
@@ -10,6 +12,20 @@ import (
 
 var ErrInvalidXML = errors.New("invalid XML error")
 
+// Attribute keys of the resources created from cells, see Transform. Each entry of the cell style is also set as
+// AttributeStylePrefix followed by the style key, e.g. "style.shape".
+const (
+	AttributeStyle       = "style"
+	AttributeStylePrefix = "style."
+	AttributeX           = "x"
+	AttributeY           = "y"
+	AttributeWidth       = "width"
+	AttributeHeight      = "height"
+)
+
+// StyleKind is the style key read from an edge cell to set the kind of the relationship, e.g. "kind=async;".
+const StyleKind = "kind"
+
 type Transformer struct {
 	mxFile  *drawioxml.MxFile
 	factory resources.ResourceFactory
@@ -20,6 +36,12 @@ func NewTransformer(mxFile *drawioxml.MxFile, factory resources.ResourceFactory)
 }
 
 // Transform parses resources from the MxFile.
+//
+// The style and geometry of each cell are passed as the resource attributes to factories implementing
+// resources.AttributedResourceFactory; other factories get no attributes.
+//
+// Cells wrapped in an <object> or <UserObject> element, which is how draw.io stores custom properties, are not read,
+// as the MxFile only holds the <mxCell> elements.
 func (t *Transformer) Transform() (*resources.ResourceCollection, error) {
 	if t.mxFile == nil {
 		return nil, ErrInvalidXML
@@ -30,7 +52,7 @@ func (t *Transformer) Transform() (*resources.ResourceCollection, error) {
 	for i := range t.mxFile.Diagram.MxGraphModel.Root.MxCells {
 		cell := t.mxFile.Diagram.MxGraphModel.Root.MxCells[i]
 
-		resource := t.factory.CreateResource(cell.ID, cell.Value, cell.Style)
+		resource := resources.CreateResource(t.factory, cell.ID, cell.Value, cell.Style, cellAttributes(&cell))
 		if resource != nil {
 			resc.AddResource(resource)
 		}
@@ -41,6 +63,10 @@ func (t *Transformer) Transform() (*resources.ResourceCollection, error) {
 		resourcesMap[resource.ID()] = resource
 	}
 
+	t.setParents(resc, resourcesMap)
+
+	edgeLabels := t.edgeLabels()
+
 	for i := range t.mxFile.Diagram.MxGraphModel.Root.MxCells {
 		cell := t.mxFile.Diagram.MxGraphModel.Root.MxCells[i]
 		if cell.Source != "" && cell.Target != "" {
@@ -48,10 +74,128 @@ func (t *Transformer) Transform() (*resources.ResourceCollection, error) {
 			target := resourcesMap[cell.Target]
 
 			if source != nil && target != nil {
-				resc.AddRelationship(source, target)
+				resc.AddRelationship(source, target, relationshipOptions(&cell, edgeLabels[cell.ID])...)
 			}
 		}
 	}
 
 	return resc, nil
 }
+
+// setParents sets the container of each resource from the cell parents. Cells that are not resources, like groups the
+// factory doesn't know, are skipped, so a resource is contained by its closest ancestor that is a resource.
+func (t *Transformer) setParents(resc *resources.ResourceCollection, resourcesMap map[string]resources.Resource) {
+	cells := t.mxFile.Diagram.MxGraphModel.Root.MxCells
+
+	parentByCellID := make(map[string]string, len(cells))
+	for i := range cells {
+		parentByCellID[cells[i].ID] = cells[i].Parent
+	}
+
+	for _, resource := range resc.Resources {
+		parentID := parentByCellID[resource.ID()]
+
+		// The number of steps is limited to the number of cells to stop on malformed files with cyclic parents.
+		for steps := 0; parentID != "" && steps < len(cells); steps++ {
+			if parent, ok := resourcesMap[parentID]; ok {
+				resc.SetParent(resource, parent)
+				break
+			}
+
+			parentID = parentByCellID[parentID]
+		}
+	}
+}
+
+// edgeLabels returns the values of the label cells draw.io creates as children of an edge, by edge ID.
+func (t *Transformer) edgeLabels() map[string]string {
+	labels := map[string]string{}
+
+	for i := range t.mxFile.Diagram.MxGraphModel.Root.MxCells {
+		cell := t.mxFile.Diagram.MxGraphModel.Root.MxCells[i]
+		if strings.HasPrefix(cell.Style, "edgeLabel") && cell.Value != "" {
+			if label, ok := labels[cell.Parent]; ok {
+				labels[cell.Parent] = label + " " + cell.Value
+			} else {
+				labels[cell.Parent] = cell.Value
+			}
+		}
+	}
+
+	return labels
+}
+
+// relationshipOptions returns the label, kind and attributes of the relationship created from an edge cell. The label
+// comes from the cell value or, when it is empty, from the label cells of the edge.
+func relationshipOptions(cell *drawioxml.MxCell, childLabel string) []resources.RelationshipOption {
+	label := cell.Value
+	if label == "" {
+		label = childLabel
+	}
+
+	options := []resources.RelationshipOption{resources.WithLabel(label)}
+
+	if cell.Style != "" {
+		style := parseStyle(cell.Style)
+
+		options = append(options,
+			resources.WithKind(style[StyleKind]), resources.WithAttribute(AttributeStyle, cell.Style))
+
+		for k, v := range style {
+			options = append(options, resources.WithAttribute(AttributeStylePrefix+k, v))
+		}
+	}
+
+	return options
+}
+
+// cellAttributes returns the style and geometry of a cell as resource attributes.
+func cellAttributes(cell *drawioxml.MxCell) map[string]string {
+	attributes := map[string]string{}
+
+	if cell.Style != "" {
+		attributes[AttributeStyle] = cell.Style
+
+		for k, v := range parseStyle(cell.Style) {
+			attributes[AttributeStylePrefix+k] = v
+		}
+	}
+
+	if geometry := cell.Geometry; geometry != nil {
+		setNonEmptyAttribute(attributes, AttributeX, geometry.X)
+		setNonEmptyAttribute(attributes, AttributeY, geometry.Y)
+
+		if geometry.Width != 0 {
+			attributes[AttributeWidth] = strconv.FormatFloat(geometry.Width, 'f', -1, 64)
+		}
+
+		if geometry.Height != 0 {
+			attributes[AttributeHeight] = strconv.FormatFloat(geometry.Height, 'f', -1, 64)
+		}
+	}
+
+	return attributes
+}
+
+func setNonEmptyAttribute(attributes map[string]string, key, value string) {
+	if value != "" {
+		attributes[key] = value
+	}
+}
+
+// parseStyle parses a draw.io style, e.g. "shape=mxgraph.aws3.lambda;fillColor=#F58534;", into a map. Entries without
+// a value, like a style name, are ignored.
+func parseStyle(style string) map[string]string {
+	entries := map[string]string{}
+
+	for _, entry := range strings.Split(style, ";") {
+		key, value, found := strings.Cut(entry, "=")
+		if !found || strings.TrimSpace(key) == "" {
+			continue
+		}
+
+		entries[strings.TrimSpace(key)] = value
+	}
+
+	return entries
+}
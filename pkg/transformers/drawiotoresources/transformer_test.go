@@ -21,6 +21,8 @@ func TestTransform(t *testing.T) {
 
 	appEngineResource := resources.NewGenericResource("APPENGINE_ID", "appengine", "mx-appengine")
 	dataFlowResource := resources.NewGenericResource("DATAFLOW_ID", "dataflow", "mx-dataflow")
+	vpcResource := resources.NewGenericResource("VPC_ID", "vpc", "mx-vpc")
+	lambdaResource := resources.NewGenericResource("LAMBDA_ID", "lambda", "lambda")
 
 	tests := []struct {
 		name      string
@@ -98,6 +100,177 @@ func TestTransform(t *testing.T) {
 				Relationships: []resources.Relationship{{Source: appEngineResource, Target: dataFlowResource}},
 			},
 		},
+		{
+			name: "Labeled And Typed Relationships",
+			args: args{
+				mxFile: &drawioxml.MxFile{
+					Diagram: drawioxml.Diagram{
+						MxGraphModel: drawioxml.MxGraphModel{
+							Root: drawioxml.Root{
+								MxCells: []drawioxml.MxCell{{
+									ID:    "APPENGINE_ID",
+									Value: "appengine",
+									Style: "mx-appengine",
+								}, {
+									ID:    "DATAFLOW_ID",
+									Value: "dataflow",
+									Style: "mx-dataflow",
+								}, {
+									ID: "3", Value: "reads from", Style: "dashed=1;kind=read;",
+									Source: "APPENGINE_ID", Target: "DATAFLOW_ID",
+								}, {
+									ID: "4", Style: "kind=write", Source: "APPENGINE_ID", Target: "DATAFLOW_ID",
+								}, {
+									ID: "5", Value: "writes to", Style: "edgeLabel;html=1;", Parent: "4", Vertex: "1",
+								}},
+							},
+						},
+					},
+				},
+				factory: mocks.NewMockResourceFactory(ctrl),
+			},
+			setup: func(mrf *mocks.MockResourceFactory) {
+				gomock.InOrder(
+					mrf.EXPECT().
+						CreateResource("APPENGINE_ID", "appengine", "mx-appengine").
+						Return(appEngineResource),
+					mrf.EXPECT().
+						CreateResource("DATAFLOW_ID", "dataflow", "mx-dataflow").
+						Return(dataFlowResource),
+					mrf.EXPECT().
+						CreateResource("3", "reads from", "dashed=1;kind=read;").
+						Return(nil),
+					mrf.EXPECT().
+						CreateResource("4", "", "kind=write").
+						Return(nil),
+					mrf.EXPECT().
+						CreateResource("5", "writes to", "edgeLabel;html=1;").
+						Return(nil),
+				)
+			},
+			want: &resources.ResourceCollection{
+				Resources: []resources.Resource{appEngineResource, dataFlowResource},
+				Relationships: []resources.Relationship{
+					{
+						Source: appEngineResource, Target: dataFlowResource, Label: "reads from", Kind: "read",
+						Attributes: map[string]string{
+							AttributeStyle:                  "dashed=1;kind=read;",
+							AttributeStylePrefix + "dashed": "1",
+							AttributeStylePrefix + "kind":   "read",
+						},
+					},
+					{
+						Source: appEngineResource, Target: dataFlowResource, Label: "writes to", Kind: "write",
+						Attributes: map[string]string{
+							AttributeStyle:                "kind=write",
+							AttributeStylePrefix + "kind": "write",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Resources Inside Containers",
+			args: args{
+				mxFile: &drawioxml.MxFile{
+					Diagram: drawioxml.Diagram{
+						MxGraphModel: drawioxml.MxGraphModel{
+							Root: drawioxml.Root{
+								MxCells: []drawioxml.MxCell{
+									{ID: "0"},
+									{ID: "1", Parent: "0"},
+									{ID: "VPC_ID", Value: "vpc", Style: "mx-vpc", Parent: "1"},
+									{ID: "GROUP_ID", Style: "group", Parent: "VPC_ID"},
+									{ID: "APPENGINE_ID", Value: "appengine", Style: "mx-appengine", Parent: "GROUP_ID"},
+									{ID: "DATAFLOW_ID", Value: "dataflow", Style: "mx-dataflow", Parent: "1"},
+								},
+							},
+						},
+					},
+				},
+				factory: mocks.NewMockResourceFactory(ctrl),
+			},
+			setup: func(mrf *mocks.MockResourceFactory) {
+				gomock.InOrder(
+					mrf.EXPECT().CreateResource("0", "", "").Return(nil),
+					mrf.EXPECT().CreateResource("1", "", "").Return(nil),
+					mrf.EXPECT().CreateResource("VPC_ID", "vpc", "mx-vpc").Return(vpcResource),
+					mrf.EXPECT().CreateResource("GROUP_ID", "", "group").Return(nil),
+					mrf.EXPECT().
+						CreateResource("APPENGINE_ID", "appengine", "mx-appengine").
+						Return(appEngineResource),
+					mrf.EXPECT().
+						CreateResource("DATAFLOW_ID", "dataflow", "mx-dataflow").
+						Return(dataFlowResource),
+				)
+			},
+			want: &resources.ResourceCollection{
+				Resources: []resources.Resource{vpcResource, appEngineResource, dataFlowResource},
+				Parents:   map[string]string{"APPENGINE_ID": "VPC_ID"},
+			},
+		},
+		{
+			name: "Resource With Style And Geometry Attributes",
+			args: args{
+				mxFile: &drawioxml.MxFile{
+					Diagram: drawioxml.Diagram{
+						MxGraphModel: drawioxml.MxGraphModel{
+							Root: drawioxml.Root{
+								MxCells: []drawioxml.MxCell{{
+									ID:       "LAMBDA_ID",
+									Value:    "lambda",
+									Style:    "shape=mxgraph.aws3.lambda;fillColor=#F58534;html=1;aspect",
+									Geometry: &drawioxml.Geometry{X: "10", Y: "20.5", Width: 76.5, Height: 93},
+								}},
+							},
+						},
+					},
+				},
+				factory: attributedFactory{},
+			},
+			want: &resources.ResourceCollection{
+				Resources: []resources.Resource{
+					resources.NewGenericResourceWithAttributes("LAMBDA_ID", "lambda", "attributed", map[string]string{
+						AttributeStyle:                     "shape=mxgraph.aws3.lambda;fillColor=#F58534;html=1;aspect",
+						AttributeStylePrefix + "shape":     "mxgraph.aws3.lambda",
+						AttributeStylePrefix + "fillColor": "#F58534",
+						AttributeStylePrefix + "html":      "1",
+						AttributeX:                         "10",
+						AttributeY:                         "20.5",
+						AttributeWidth:                     "76.5",
+						AttributeHeight:                    "93",
+					}),
+				},
+			},
+		},
+		{
+			name: "Factory Without Attributes",
+			args: args{
+				mxFile: &drawioxml.MxFile{
+					Diagram: drawioxml.Diagram{
+						MxGraphModel: drawioxml.MxGraphModel{
+							Root: drawioxml.Root{
+								MxCells: []drawioxml.MxCell{{
+									ID:       "LAMBDA_ID",
+									Value:    "lambda",
+									Style:    "lambda;fillColor=#F58534;",
+									Geometry: &drawioxml.Geometry{Width: 78},
+								}},
+							},
+						},
+					},
+				},
+				factory: mocks.NewMockResourceFactory(ctrl),
+			},
+			setup: func(mrf *mocks.MockResourceFactory) {
+				mrf.EXPECT().
+					CreateResource("LAMBDA_ID", "lambda", "lambda;fillColor=#F58534;").
+					Return(lambdaResource)
+			},
+			want: &resources.ResourceCollection{
+				Resources: []resources.Resource{lambdaResource},
+			},
+		},
 		{
 			name: "when XML is invalid should return an error",
 			args: args{
@@ -123,4 +296,18 @@ func TestTransform(t *testing.T) {
 			require.Equal(t, tc.want, got)
 		})
 	}
+
+	// The resources returned by the factory are not changed.
+	require.Nil(t, appEngineResource.Attributes())
+	require.Nil(t, lambdaResource.Attributes())
+}
+
+type attributedFactory struct{}
+
+func (attributedFactory) CreateResource(_, _, _ string) resources.Resource { return nil }
+
+func (attributedFactory) CreateResourceWithAttributes(
+	id, value, _ string, attributes map[string]string,
+) resources.Resource {
+	return resources.NewGenericResourceWithAttributes(id, value, "attributed", attributes)
 }
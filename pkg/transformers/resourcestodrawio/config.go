@@ -1,5 +1,26 @@
 package resourcestodrawio
 
+import (
+	"github.com/diagram-code-generator/resources/pkg/layout"
+	"github.com/diagram-code-generator/resources/pkg/parser/graphviz/dot"
+)
+
 type Config struct {
 	NodeStyles map[string]string
+	EdgeStyles map[string]string
+
+	// Layouter lays out the resources. When it is nil, layout.New lays them out with the layout options below.
+	Layouter layout.Layouter
+
+	// Engine, Direction, NodeSep and RankSep are the options of layout.New. Empty fields keep the Graphviz defaults:
+	// the dot engine, top to bottom. Engine is the Graphviz layout engine, e.g. dot.EngineNeato, ignored by the
+	// layered layout. NewTransformer checks it, and Transform returns its dot.ErrUnsupportedEngine.
+	Engine    dot.Engine
+	Direction dot.DiagramDirection
+	// NodeSep and RankSep are the space between the nodes of a rank and between the ranks, in inches.
+	NodeSep float64
+	RankSep float64
+	// Scale multiplies the positions and sizes of the layout, e.g. 1.5 to spread a diagram over a wide page. Zero
+	// keeps the size of the layout.
+	Scale float64
 }
@@ -2,49 +2,149 @@ package resourcestodrawio
 
 import (
 	"crypto/rand"
+	"encoding/xml"
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 
 	pdrawioxml "github.com/joselitofilho/drawio-parser-go/pkg/parser/xml"
 
-	svg "github.com/diagram-code-generator/resources/pkg/parser/graphviz/svg"
+	"github.com/diagram-code-generator/resources/pkg/layout"
 	"github.com/diagram-code-generator/resources/pkg/resources"
 )
 
 // Help tests.
-var randRead = rand.Read
+var (
+	randRead    = rand.Read
+	newLayouter = layout.New
+)
+
+const (
+	// containerPadding is the space between a container border and its children.
+	containerPadding = 20
+	// containerLabelHeight is the extra space above the children of a container for its label.
+	containerLabelHeight = 20
+	// containerStyle makes draw.io keep the children of a vertex inside it.
+	containerStyle = "container=1;"
+	// coordinatePrecision rounds the computed coordinates to four decimal places.
+	coordinatePrecision = 1e4
+	// connectionPrecision rounds the relative positions of the edge connections to two decimal places.
+	connectionPrecision = 1e2
+)
+
+// styleAttribute is the relationship attribute holding the draw.io style of an imported edge.
+const styleAttribute = "style"
+
+// DiffStyles holds the styles appended to the cells of the added, removed and modified resources and relationships
+// of a diff.
+var DiffStyles = map[resources.DiffStatus]string{
+	resources.DiffAdded:    "strokeColor=#2e7d32;fontColor=#2e7d32;",
+	resources.DiffRemoved:  "strokeColor=#c62828;fontColor=#c62828;fontStyle=8;dashed=1;",
+	resources.DiffModified: "strokeColor=#ff8f00;fontColor=#ff8f00;",
+}
 
 type Transformer struct {
 	config        *Config
 	resCollection *resources.ResourceCollection
+
+	// diff is set for the transformers created by NewDiffTransformer.
+	diff bool
+	// err is the error of the Config, returned by Transform.
+	err error
 }
 
+// NewTransformer creates a transformer of a resource collection. An unsupported Config.Engine is returned by
+// Transform.
 func NewTransformer(resCollection *resources.ResourceCollection, config *Config) *Transformer {
 	return &Transformer{
 		config:        config,
 		resCollection: resCollection,
+		err:           validateConfig(config),
+	}
+}
+
+// NewDiffTransformer creates a transformer of the union of two resource collections, built by
+// resources.DiffCollection, whose added, removed and modified cells get the styles in DiffStyles.
+func NewDiffTransformer(before, after *resources.ResourceCollection, config *Config) *Transformer {
+	return &Transformer{
+		config:        config,
+		resCollection: resources.DiffCollection(before, after),
+		diff:          true,
+		err:           validateConfig(config),
+	}
+}
+
+// validateConfig returns the error of an unsupported engine in the Config.
+func validateConfig(config *Config) error {
+	if config == nil {
+		return nil
+	}
+
+	return config.Engine.Validate()
+}
+
+// Transform lays out the resources with the Layouter of the Config and returns the draw.io file of the diagram, or
+// the error of the layout. The edges leave and reach the vertices where their routes do, but their waypoints are only
+// written by TransformXML, as the MxFile geometry can't hold them.
+func (t *Transformer) Transform() (*pdrawioxml.MxFile, error) {
+	mxFile, _, err := t.transform()
+
+	return mxFile, err
+}
+
+// TransformXML lays out the resources like Transform and returns the draw.io XML of the diagram, with the waypoints of
+// the edge routes.
+func (t *Transformer) TransformXML() ([]byte, error) {
+	mxFile, waypoints, err := t.transform()
+	if err != nil {
+		return nil, err
 	}
+
+	return xml.MarshalIndent(newXMLFile(mxFile, waypoints), "", "  ")
 }
 
-func (t *Transformer) Transform() *pdrawioxml.MxFile {
+// transform returns the draw.io file of the diagram and the waypoints of its edges, by cell ID.
+func (t *Transformer) transform() (*pdrawioxml.MxFile, map[string][]layout.Point, error) {
+	if t.err != nil {
+		return nil, nil, t.err
+	}
+
 	if t.config == nil {
 		t.config = &Config{}
 	}
 
-	svgData := svg.NewSVGDiagram().Build(t.resCollection)
+	layouter := t.config.Layouter
+	if layouter == nil {
+		layouter = newLayouter(&layout.Config{
+			Engine:    t.config.Engine,
+			Direction: t.config.Direction,
+			NodeSep:   t.config.NodeSep,
+			RankSep:   t.config.RankSep,
+		})
+	}
+
+	result, err := layouter.Layout(t.resCollection)
+	if err != nil {
+		return nil, nil, fmt.Errorf("laying out resources: %w", err)
+	}
+
+	mxCells, waypoints := t.buildMxCells(t.resCollection, result)
 
 	return &pdrawioxml.MxFile{
 		Diagram: pdrawioxml.Diagram{
 			MxGraphModel: pdrawioxml.MxGraphModel{
 				Root: pdrawioxml.Root{
-					MxCells: t.buildMxCells(t.resCollection, svgData),
+					MxCells: mxCells,
 				},
 			},
 		},
-	}
+	}, waypoints, nil
 }
 
-func (t *Transformer) buildMxCells(resCollection *resources.ResourceCollection, svgData *svg.SVG) []pdrawioxml.MxCell {
+func (t *Transformer) buildMxCells(
+	resCollection *resources.ResourceCollection, result *layout.Layout,
+) ([]pdrawioxml.MxCell, map[string][]layout.Point) {
 	edgeID := len(resCollection.Resources) + 1
 	baseID := generateBaseID(20)
 
@@ -52,34 +152,74 @@ func (t *Transformer) buildMxCells(resCollection *resources.ResourceCollection,
 
 	mxCells = append(mxCells, pdrawioxml.MxCell{ID: "0"}, pdrawioxml.MxCell{ID: "1", Parent: "0"})
 
-	for _, node := range svgData.G.Nodes {
-		content := node.Text.Content
-		if content == "" {
+	placement := newContainerLayout(resCollection, result, t.config.Scale)
+	emitted := map[string]struct{}{}
+
+	for _, res := range resCollection.Resources {
+		id := res.ID()
+
+		// Containers are appended before their first child.
+		if _, ok := emitted[id]; ok || resCollection.IsContainer(res) {
 			continue
 		}
 
-		parts := strings.Split(content, svg.ResourceInfoSeparator)
-		id, value, resType := parts[0], parts[1], parts[2]
-		x, y := node.Text.X, node.Text.Y
+		emitted[id] = struct{}{}
+
+		b := placement.boundsByID[id]
+		x, y := formatCoordinate(b.minX), formatCoordinate(b.minY)
 
 		sourceID := fmt.Sprintf("%s-%s", baseID, id)
+		parentID := "1"
+		style := t.config.NodeStyles[res.ResourceType()]
+
+		if parent := resCollection.Parent(res); parent != nil {
+			mxCells = t.appendContainer(mxCells, placement, parent, baseID, emitted, len(resCollection.Resources))
+
+			parentID = fmt.Sprintf("%s-%s", baseID, parent.ID())
+			x, y = placement.relativePosition(id, parent.ID())
+		}
+
+		style += t.diffStyle(resources.DiffStatusOf(res))
 
 		mxCells = append(mxCells, pdrawioxml.MxCell{
 			ID:       sourceID,
-			Value:    value,
-			Style:    t.config.NodeStyles[resType],
+			Value:    res.Value(),
+			Style:    style,
 			Vertex:   "1",
-			Parent:   "1",
-			Geometry: &pdrawioxml.Geometry{X: x, Y: y, Width: 40, Height: 40, As: "geometry"},
+			Parent:   parentID,
+			Geometry: b.geometry(x, y),
 		})
 	}
 
-	for _, rel := range resCollection.Relationships {
+	waypoints := map[string][]layout.Point{}
+
+	for i, rel := range resCollection.Relationships {
+		// Relationships without both endpoints have no edge to draw.
+		if rel.Source == nil || rel.Target == nil {
+			continue
+		}
+
+		cellID := fmt.Sprintf("%s-%d", baseID, edgeID)
 		sourceID := fmt.Sprintf("%s-%s", baseID, rel.Source.ID())
 		targetID := fmt.Sprintf("%s-%s", baseID, rel.Target.ID())
 
+		// A Layouter returning fewer routes than relationships leaves the last edges without one.
+		var edge layout.Edge
+		if i < len(result.Edges) {
+			edge = result.Edges[i]
+		}
+
+		style := t.edgeStyle(rel) + connectionStyle(rel, edge, placement) +
+			t.diffStyle(resources.RelationshipDiffStatus(rel))
+
+		if points := edgeWaypoints(edge, placement); len(points) > 0 {
+			waypoints[cellID] = points
+		}
+
 		mxCells = append(mxCells, pdrawioxml.MxCell{
-			ID:       fmt.Sprintf("%s-%d", baseID, edgeID),
+			ID:       cellID,
+			Value:    rel.Label,
+			Style:    style,
 			Source:   sourceID,
 			Target:   targetID,
 			Edge:     "1",
@@ -90,7 +230,98 @@ func (t *Transformer) buildMxCells(resCollection *resources.ResourceCollection,
 		edgeID++
 	}
 
-	return mxCells
+	return mxCells, waypoints
+}
+
+// appendContainer appends the cell of a container, after the cells of its own containers, unless it was already
+// appended. The depth is limited to stop on cyclic hierarchies.
+func (t *Transformer) appendContainer(
+	mxCells []pdrawioxml.MxCell, placement *containerLayout, container resources.Resource, baseID string,
+	emitted map[string]struct{}, depth int,
+) []pdrawioxml.MxCell {
+	if _, ok := emitted[container.ID()]; ok || depth == 0 {
+		return mxCells
+	}
+
+	emitted[container.ID()] = struct{}{}
+
+	parentID := "1"
+	b := placement.boundsOf(container.ID(), depth)
+	x, y := formatCoordinate(b.minX), formatCoordinate(b.minY)
+
+	if parent := t.resCollection.Parent(container); parent != nil {
+		mxCells = t.appendContainer(mxCells, placement, parent, baseID, emitted, depth-1)
+
+		parentID = fmt.Sprintf("%s-%s", baseID, parent.ID())
+		x, y = placement.relativePosition(container.ID(), parent.ID())
+	}
+
+	style := t.config.NodeStyles[container.ResourceType()]
+	if !strings.Contains(style, containerStyle) {
+		style += containerStyle
+	}
+
+	style += t.diffStyle(resources.DiffStatusOf(container))
+
+	return append(mxCells, pdrawioxml.MxCell{
+		ID:       fmt.Sprintf("%s-%s", baseID, container.ID()),
+		Value:    container.Value(),
+		Style:    style,
+		Vertex:   "1",
+		Parent:   parentID,
+		Geometry: b.geometry(x, y),
+	})
+}
+
+// edgeStyle returns the style configured for the relationship kind or, when there is none, the style the relationship
+// was imported with.
+func (t *Transformer) edgeStyle(rel resources.Relationship) string {
+	if style, ok := t.config.EdgeStyles[rel.Kind]; ok {
+		return style
+	}
+
+	return rel.Attributes[styleAttribute]
+}
+
+// diffStyle returns the style of the diff status when the transformer draws a diff.
+func (t *Transformer) diffStyle(status resources.DiffStatus) string {
+	if !t.diff {
+		return ""
+	}
+
+	return DiffStyles[status]
+}
+
+// connectionStyle returns the exit and entry points of the edge of a relationship, where its route leaves the source
+// vertex and reaches the target one.
+func connectionStyle(rel resources.Relationship, edge layout.Edge, placement *containerLayout) string {
+	points := edge.Points
+	sourceBounds, okSource := placement.boundsByID[rel.Source.ID()]
+	targetBounds, okTarget := placement.boundsByID[rel.Target.ID()]
+
+	if len(points) < 2 || !okSource || !okTarget {
+		return ""
+	}
+
+	exitX, exitY := sourceBounds.relativePoint(placement.scalePoint(points[0]))
+	entryX, entryY := targetBounds.relativePoint(placement.scalePoint(points[len(points)-1]))
+
+	return fmt.Sprintf("exitX=%s;exitY=%s;entryX=%s;entryY=%s;", exitX, exitY, entryX, entryY)
+}
+
+// edgeWaypoints returns the points of the route of an edge between its first and last ones, at the scale of the
+// vertices.
+func edgeWaypoints(edge layout.Edge, placement *containerLayout) []layout.Point {
+	if len(edge.Points) < 3 {
+		return nil
+	}
+
+	waypoints := make([]layout.Point, 0, len(edge.Points)-2)
+	for _, p := range edge.Points[1 : len(edge.Points)-1] {
+		waypoints = append(waypoints, placement.scalePoint(p))
+	}
+
+	return waypoints
 }
 
 func generateBaseID(length int) string {
@@ -112,3 +343,123 @@ func generateBaseID(length int) string {
 
 	return string(bytes)
 }
+
+type bounds struct {
+	minX, minY, maxX, maxY float64
+}
+
+// geometry returns the draw.io geometry of a cell at the given position, with the size of the bounds.
+func (b bounds) geometry(x, y string) *pdrawioxml.Geometry {
+	return &pdrawioxml.Geometry{
+		X: x, Y: y, Width: roundCoordinate(b.maxX - b.minX), Height: roundCoordinate(b.maxY - b.minY), As: "geometry",
+	}
+}
+
+// relativePoint returns the position of a point relative to the bounds, from 0 to 1, as draw.io expects for the
+// connections of the edges.
+func (b bounds) relativePoint(p layout.Point) (x, y string) {
+	relative := func(v, minV, maxV float64) string {
+		if maxV == minV {
+			return "0.5"
+		}
+
+		r := min(max((v-minV)/(maxV-minV), 0), 1)
+
+		return strconv.FormatFloat(math.Round(r*connectionPrecision)/connectionPrecision, 'f', -1, 64)
+	}
+
+	return relative(p.X, b.minX, b.maxX), relative(p.Y, b.minY, b.maxY)
+}
+
+// containerLayout places the containers around the vertices of their children, which are positioned by the layout.
+type containerLayout struct {
+	resCollection *resources.ResourceCollection
+	resourcesByID map[string]resources.Resource
+	boundsByID    map[string]bounds
+	// scale multiplies the positions and sizes of the layout.
+	scale float64
+}
+
+func newContainerLayout(
+	resCollection *resources.ResourceCollection, result *layout.Layout, scale float64,
+) *containerLayout {
+	if scale <= 0 {
+		scale = 1
+	}
+
+	placement := &containerLayout{
+		resCollection: resCollection,
+		resourcesByID: make(map[string]resources.Resource, len(resCollection.Resources)),
+		boundsByID:    make(map[string]bounds, len(result.Nodes)),
+		scale:         scale,
+	}
+
+	for _, res := range resCollection.Resources {
+		placement.resourcesByID[res.ID()] = res
+	}
+
+	for id, r := range result.Nodes {
+		placement.boundsByID[id] = bounds{
+			minX: r.X * scale, minY: r.Y * scale, maxX: (r.X + r.Width) * scale, maxY: (r.Y + r.Height) * scale,
+		}
+	}
+
+	return placement
+}
+
+// boundsOf returns the bounds of a vertex or, for a container, the bounds around all its children.
+func (l *containerLayout) boundsOf(id string, depth int) bounds {
+	if b, ok := l.boundsByID[id]; ok || depth == 0 {
+		return b
+	}
+
+	var (
+		b     bounds
+		first = true
+	)
+
+	for _, child := range l.resCollection.Children(l.resourcesByID[id]) {
+		cb := l.boundsOf(child.ID(), depth-1)
+		if first {
+			b, first = cb, false
+			continue
+		}
+
+		b = bounds{
+			minX: min(b.minX, cb.minX), minY: min(b.minY, cb.minY),
+			maxX: max(b.maxX, cb.maxX), maxY: max(b.maxY, cb.maxY),
+		}
+	}
+
+	b = bounds{
+		minX: b.minX - containerPadding, minY: b.minY - containerPadding - containerLabelHeight,
+		maxX: b.maxX + containerPadding, maxY: b.maxY + containerPadding,
+	}
+
+	l.boundsByID[id] = b
+
+	return b
+}
+
+// scalePoint returns a point of the layout at the scale of the vertices.
+func (l *containerLayout) scalePoint(p layout.Point) layout.Point {
+	return layout.Point{X: p.X * l.scale, Y: p.Y * l.scale}
+}
+
+// relativePosition returns the position of a vertex relative to its container, as draw.io expects for children.
+func (l *containerLayout) relativePosition(id, parentID string) (x, y string) {
+	b := l.boundsOf(id, len(l.resourcesByID))
+	pb := l.boundsOf(parentID, len(l.resourcesByID))
+
+	return formatCoordinate(b.minX - pb.minX), formatCoordinate(b.minY - pb.minY)
+}
+
+// formatCoordinate formats a coordinate with the same precision used by the Graphviz SVG output.
+func formatCoordinate(v float64) string {
+	return strconv.FormatFloat(roundCoordinate(v), 'f', -1, 64)
+}
+
+// roundCoordinate rounds a coordinate or size to the precision used by the Graphviz SVG output.
+func roundCoordinate(v float64) float64 {
+	return math.Round(v*coordinatePrecision) / coordinatePrecision
+}
@@ -0,0 +1,188 @@
+package resourcestodrawio
+
+import (
+	"crypto/rand"
+	"encoding/xml"
+	"errors"
+	"testing"
+
+	drawioxml "github.com/joselitofilho/drawio-parser-go/pkg/parser/xml"
+	"github.com/stretchr/testify/require"
+
+	"github.com/diagram-code-generator/resources/pkg/layout"
+	"github.com/diagram-code-generator/resources/pkg/parser/graphviz/dot"
+	"github.com/diagram-code-generator/resources/pkg/resources"
+)
+
+type failingLayouter struct{ err error }
+
+func (l failingLayouter) Layout(*resources.ResourceCollection) (*layout.Layout, error) {
+	return nil, l.err
+}
+
+type fixedLayouter struct{ layout *layout.Layout }
+
+func (l fixedLayouter) Layout(*resources.ResourceCollection) (*layout.Layout, error) {
+	return l.layout, nil
+}
+
+func TestTransformer_Transform_LayeredLayout(t *testing.T) {
+	randRead = func(b []byte) (n int, err error) { return len(b), nil }
+	defer func() { randRead = rand.Read }()
+
+	lambda1 := resources.NewGenericResource("1", "lambda1", "lambda")
+	lambda2 := resources.NewGenericResource("2", "lambda2", "lambda")
+	vpc := resources.NewGenericResource("10", "vpc", "vpc")
+
+	got, err := NewTransformer(&resources.ResourceCollection{
+		Resources:     []resources.Resource{vpc, lambda1, lambda2},
+		Relationships: []resources.Relationship{{Source: lambda1, Target: lambda2}},
+		Parents:       map[string]string{lambda2.ID(): vpc.ID()},
+	}, &Config{
+		Layouter: layout.NewLayered(&layout.Config{Direction: dot.DirectionLeftToRight}),
+		Scale:    2,
+	}).Transform()
+
+	require.NoError(t, err)
+	require.Equal(t, []drawioxml.MxCell{
+		{ID: "0"},
+		{ID: "1", Parent: "0"},
+		{
+			ID: "aaaaaaaaaaaaaaa-1", Value: "lambda1", Parent: "1", Vertex: "1",
+			Geometry: &drawioxml.Geometry{X: "0", Y: "0", Width: 80, Height: 80, As: "geometry"},
+		},
+		{
+			ID: "aaaaaaaaaaaaaaa-10", Value: "vpc", Style: containerStyle, Parent: "1", Vertex: "1",
+			Geometry: &drawioxml.Geometry{X: "252", Y: "-40", Width: 120, Height: 140, As: "geometry"},
+		},
+		{
+			ID: "aaaaaaaaaaaaaaa-2", Value: "lambda2", Parent: "aaaaaaaaaaaaaaa-10", Vertex: "1",
+			Geometry: &drawioxml.Geometry{X: "20", Y: "40", Width: 80, Height: 80, As: "geometry"},
+		},
+		{
+			ID: "aaaaaaaaaaaaaaa-4", Parent: "1", Edge: "1", Style: "exitX=1;exitY=0.5;entryX=0;entryY=0.5;",
+			Source: "aaaaaaaaaaaaaaa-1", Target: "aaaaaaaaaaaaaaa-2",
+			Geometry: &drawioxml.Geometry{As: "geometry"},
+		},
+	}, got.Diagram.MxGraphModel.Root.MxCells)
+}
+
+func TestTransformer_Transform_LayoutFails(t *testing.T) {
+	errDummy := errors.New("dummy error")
+
+	lambda := resources.NewGenericResource("1", "lambda1", "lambda")
+
+	got, err := NewTransformer(&resources.ResourceCollection{Resources: []resources.Resource{lambda}},
+		&Config{Layouter: failingLayouter{err: errDummy}}).Transform()
+
+	require.ErrorIs(t, err, errDummy)
+	require.Nil(t, got)
+}
+
+func TestTransformer_Transform_MissingEdges(t *testing.T) {
+	randRead = func(b []byte) (n int, err error) { return len(b), nil }
+	defer func() { randRead = rand.Read }()
+
+	lambda1 := resources.NewGenericResource("1", "lambda1", "lambda")
+	lambda2 := resources.NewGenericResource("2", "lambda2", "lambda")
+
+	got, err := NewTransformer(&resources.ResourceCollection{
+		Resources:     []resources.Resource{lambda1, lambda2},
+		Relationships: []resources.Relationship{{Source: lambda1, Target: lambda2}},
+	}, &Config{Layouter: fixedLayouter{layout: &layout.Layout{Nodes: map[string]layout.Rect{
+		"1": {X: 0, Y: 0, Width: 40, Height: 40},
+		"2": {X: 0, Y: 80, Width: 40, Height: 40},
+	}}}}).Transform()
+
+	require.NoError(t, err)
+
+	cells := got.Diagram.MxGraphModel.Root.MxCells
+	require.Len(t, cells, 5)
+	require.Equal(t, drawioxml.MxCell{
+		ID: "aaaaaaaaaaaaaaa-3", Parent: "1", Edge: "1", Source: "aaaaaaaaaaaaaaa-1", Target: "aaaaaaaaaaaaaaa-2",
+		Geometry: &drawioxml.Geometry{As: "geometry"},
+	}, cells[4])
+}
+
+func TestTransformer_Transform_UnsupportedEngine(t *testing.T) {
+	lambda := resources.NewGenericResource("1", "lambda1", "lambda")
+	resc := &resources.ResourceCollection{Resources: []resources.Resource{lambda}}
+
+	got, err := NewTransformer(resc, &Config{Engine: "unknown"}).Transform()
+
+	require.ErrorIs(t, err, dot.ErrUnsupportedEngine)
+	require.Nil(t, got)
+
+	xmlData, err := NewDiffTransformer(resc, resc, &Config{Engine: "unknown"}).TransformXML()
+
+	require.ErrorIs(t, err, dot.ErrUnsupportedEngine)
+	require.Nil(t, xmlData)
+}
+
+func TestTransformer_Transform_NilEndpoint(t *testing.T) {
+	randRead = func(b []byte) (n int, err error) { return len(b), nil }
+	defer func() { randRead = rand.Read }()
+
+	lambda1 := resources.NewGenericResource("1", "lambda1", "lambda")
+	lambda2 := resources.NewGenericResource("2", "lambda2", "lambda")
+
+	got, err := NewTransformer(&resources.ResourceCollection{
+		Resources: []resources.Resource{lambda1, lambda2},
+		Relationships: []resources.Relationship{
+			{Source: lambda1}, {Source: lambda1, Target: lambda2}, {Target: lambda2},
+		},
+	}, nil).Transform()
+
+	require.NoError(t, err)
+
+	cells := got.Diagram.MxGraphModel.Root.MxCells
+	require.Len(t, cells, 5)
+	require.Equal(t, "1", cells[4].Edge)
+	require.Equal(t, "aaaaaaaaaaaaaaa-1", cells[4].Source)
+	require.Equal(t, "aaaaaaaaaaaaaaa-2", cells[4].Target)
+}
+
+func TestTransformer_TransformXML_Waypoints(t *testing.T) {
+	randRead = func(b []byte) (n int, err error) { return len(b), nil }
+	defer func() { randRead = rand.Read }()
+
+	a := resources.NewGenericResource("1", "a", "lambda")
+	b := resources.NewGenericResource("2", "b", "lambda")
+	c := resources.NewGenericResource("3", "c", "lambda")
+
+	// The edge from a to c spans two ranks, so it is routed around b.
+	transformer := NewTransformer(&resources.ResourceCollection{
+		Resources: []resources.Resource{a, b, c},
+		Relationships: []resources.Relationship{
+			{Source: a, Target: b}, {Source: b, Target: c}, {Source: a, Target: c},
+		},
+	}, &Config{Layouter: layout.NewLayered(nil)})
+
+	got, err := transformer.TransformXML()
+	require.NoError(t, err)
+
+	var file xmlFile
+
+	require.NoError(t, xml.Unmarshal(got, &file))
+
+	cells := file.Diagram.MxGraphModel.Root.MxCells
+	require.Len(t, cells, 8)
+	require.Nil(t, cells[5].Geometry.Points)
+	require.Nil(t, cells[6].Geometry.Points)
+	require.Equal(t, &xmlPoints{As: "points", Points: []xmlPoint{{X: "58", Y: "96"}}}, cells[7].Geometry.Points)
+
+	// The XML is still read by the draw.io parser, which skips the waypoints.
+	var mxFile drawioxml.MxFile
+
+	require.NoError(t, xml.Unmarshal(got, &mxFile))
+
+	want, err := transformer.Transform()
+	require.NoError(t, err)
+
+	wantXML, err := xml.Marshal(want)
+	require.NoError(t, err)
+
+	gotXML, err := xml.Marshal(&mxFile)
+	require.NoError(t, err)
+	require.Equal(t, string(wantXML), string(gotXML))
+}
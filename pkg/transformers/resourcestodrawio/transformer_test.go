@@ -7,9 +7,10 @@ import (
 
 	drawioxml "github.com/joselitofilho/drawio-parser-go/pkg/parser/xml"
 
+	"github.com/diagram-code-generator/resources/pkg/layout"
+	"github.com/diagram-code-generator/resources/pkg/parser/graphviz/dot"
 	"github.com/diagram-code-generator/resources/pkg/resources"
 
-	"github.com/goccy/go-graphviz"
 	"github.com/stretchr/testify/require"
 )
 
@@ -17,17 +18,24 @@ func TestTransformer_Transform(t *testing.T) {
 	const lambdaStyle = "outlineConnect=0;dashed=0;verticalLabelPosition=bottom;verticalAlign=top;align=center;html=1;" +
 		"shape=mxgraph.aws3.lambda;fillColor=#F58534;gradientColor=none;aspect=fixed;"
 
+	// connectedVertically leaves the bottom of the source and reaches the top of the target.
+	const connectedVertically = "exitX=0.5;exitY=1;entryX=0.5;entryY=0;"
+
 	errDummy := errors.New("dummy error")
 
+	// The default layouter depends on cgo, so the expectations are pinned to the layered layout.
+	newLayouter = func(config *layout.Config) layout.Layouter { return layout.NewLayered(config) }
+	defer func() { newLayouter = layout.New }()
+
 	type fields struct {
 		config        *Config
 		resCollection *resources.ResourceCollection
-		g             *graphviz.Graphviz
 	}
 
 	lambda1 := resources.NewGenericResource("1", "lambda1", "lambda")
 	lambda2 := resources.NewGenericResource("2", "lambda2", "lambda")
 	lambda3 := resources.NewGenericResource("3", "lambda3", "lambda")
+	vpc := resources.NewGenericResource("10", "vpc", "vpc")
 
 	tests := []struct {
 		name   string
@@ -47,7 +55,6 @@ func TestTransformer_Transform(t *testing.T) {
 						{Source: lambda1, Target: lambda2},
 					},
 				},
-				g: graphviz.New(),
 			},
 			setup: func() (tearDown func()) {
 				randRead = func(b []byte) (n int, err error) {
@@ -66,23 +73,185 @@ func TestTransformer_Transform(t *testing.T) {
 					{
 						ID: "aaaaaaaaaaaaaaa-1", Value: "lambda1", Style: lambdaStyle, Parent: "1", Vertex: "1",
 						Geometry: &drawioxml.Geometry{
-							X: "96.8597", Y: "-85.8", Width: 40, Height: 40, As: "geometry",
+							X: "0", Y: "0", Width: 40, Height: 40, As: "geometry",
 						},
 					},
 					{
 						ID: "aaaaaaaaaaaaaaa-2", Value: "lambda2", Style: lambdaStyle, Parent: "1", Vertex: "1",
 						Geometry: &drawioxml.Geometry{
-							X: "96.8597", Y: "-13.8", Width: 40, Height: 40, As: "geometry",
+							X: "0", Y: "76", Width: 40, Height: 40, As: "geometry",
 						},
 					},
 					{
 						ID: "aaaaaaaaaaaaaaa-3", Value: "lambda3", Style: lambdaStyle, Parent: "1", Vertex: "1",
 						Geometry: &drawioxml.Geometry{
-							X: "308.8597", Y: "-85.8", Width: 40, Height: 40, As: "geometry",
+							X: "58", Y: "0", Width: 40, Height: 40, As: "geometry",
 						},
 					},
 					{
-						ID: "aaaaaaaaaaaaaaa-4", Parent: "1", Edge: "1",
+						ID: "aaaaaaaaaaaaaaa-4", Style: connectedVertically, Parent: "1", Edge: "1",
+						Source: "aaaaaaaaaaaaaaa-1", Target: "aaaaaaaaaaaaaaa-2",
+						Geometry: &drawioxml.Geometry{As: "geometry"},
+					},
+				}},
+			}}},
+		},
+		{
+			name: "labeled and typed relationships",
+			fields: fields{
+				config: &Config{
+					EdgeStyles: map[string]string{"async": "dashed=1;"},
+				},
+				resCollection: &resources.ResourceCollection{
+					Resources: []resources.Resource{lambda1, lambda2, lambda3},
+					Relationships: []resources.Relationship{
+						{Source: lambda1, Target: lambda2, Label: "invokes", Kind: "async"},
+						{Source: lambda1, Target: lambda3, Attributes: map[string]string{"style": "endArrow=none;"}},
+					},
+				},
+			},
+			setup: func() (tearDown func()) {
+				randRead = func(b []byte) (n int, err error) {
+					require.Len(t, b, 15)
+					return 15, nil
+				}
+
+				return func() {
+					randRead = rand.Read
+				}
+			},
+			want: &drawioxml.MxFile{Diagram: drawioxml.Diagram{MxGraphModel: drawioxml.MxGraphModel{
+				Root: drawioxml.Root{MxCells: []drawioxml.MxCell{
+					{ID: "0"},
+					{ID: "1", Parent: "0"},
+					{
+						ID: "aaaaaaaaaaaaaaa-1", Value: "lambda1", Parent: "1", Vertex: "1",
+						Geometry: &drawioxml.Geometry{
+							X: "29", Y: "0", Width: 40, Height: 40, As: "geometry",
+						},
+					},
+					{
+						ID: "aaaaaaaaaaaaaaa-2", Value: "lambda2", Parent: "1", Vertex: "1",
+						Geometry: &drawioxml.Geometry{
+							X: "0", Y: "76", Width: 40, Height: 40, As: "geometry",
+						},
+					},
+					{
+						ID: "aaaaaaaaaaaaaaa-3", Value: "lambda3", Parent: "1", Vertex: "1",
+						Geometry: &drawioxml.Geometry{
+							X: "58", Y: "76", Width: 40, Height: 40, As: "geometry",
+						},
+					},
+					{
+						ID: "aaaaaaaaaaaaaaa-4", Value: "invokes", Parent: "1", Edge: "1",
+						Style:  "dashed=1;" + connectedVertically,
+						Source: "aaaaaaaaaaaaaaa-1", Target: "aaaaaaaaaaaaaaa-2",
+						Geometry: &drawioxml.Geometry{As: "geometry"},
+					},
+					{
+						ID: "aaaaaaaaaaaaaaa-5", Parent: "1", Edge: "1",
+						Style:  "endArrow=none;" + connectedVertically,
+						Source: "aaaaaaaaaaaaaaa-1", Target: "aaaaaaaaaaaaaaa-3",
+						Geometry: &drawioxml.Geometry{As: "geometry"},
+					},
+				}},
+			}}},
+		},
+		{
+			name: "layout options",
+			fields: fields{
+				config: &Config{Direction: dot.DirectionLeftToRight, RankSep: 1, Scale: 2},
+				resCollection: &resources.ResourceCollection{
+					Resources: []resources.Resource{lambda1, lambda2},
+					Relationships: []resources.Relationship{
+						{Source: lambda1, Target: lambda2},
+					},
+				},
+			},
+			setup: func() (tearDown func()) {
+				randRead = func(b []byte) (n int, err error) {
+					require.Len(t, b, 15)
+					return 15, nil
+				}
+
+				return func() {
+					randRead = rand.Read
+				}
+			},
+			want: &drawioxml.MxFile{Diagram: drawioxml.Diagram{MxGraphModel: drawioxml.MxGraphModel{
+				Root: drawioxml.Root{MxCells: []drawioxml.MxCell{
+					{ID: "0"},
+					{ID: "1", Parent: "0"},
+					{
+						ID: "aaaaaaaaaaaaaaa-1", Value: "lambda1", Parent: "1", Vertex: "1",
+						Geometry: &drawioxml.Geometry{
+							X: "0", Y: "0", Width: 80, Height: 80, As: "geometry",
+						},
+					},
+					{
+						ID: "aaaaaaaaaaaaaaa-2", Value: "lambda2", Parent: "1", Vertex: "1",
+						Geometry: &drawioxml.Geometry{
+							X: "224", Y: "0", Width: 80, Height: 80, As: "geometry",
+						},
+					},
+					{
+						ID: "aaaaaaaaaaaaaaa-3", Parent: "1", Edge: "1",
+						Style:  "exitX=1;exitY=0.5;entryX=0;entryY=0.5;",
+						Source: "aaaaaaaaaaaaaaa-1", Target: "aaaaaaaaaaaaaaa-2",
+						Geometry: &drawioxml.Geometry{As: "geometry"},
+					},
+				}},
+			}}},
+		},
+		{
+			name: "containers are nested cells",
+			fields: fields{
+				config: &Config{
+					NodeStyles: map[string]string{"vpc": "shape=mxgraph.aws4.group;"},
+				},
+				resCollection: &resources.ResourceCollection{
+					Resources: []resources.Resource{vpc, lambda1, lambda2},
+					Relationships: []resources.Relationship{
+						{Source: lambda1, Target: lambda2},
+					},
+					Parents: map[string]string{lambda1.ID(): vpc.ID(), lambda2.ID(): vpc.ID()},
+				},
+			},
+			setup: func() (tearDown func()) {
+				randRead = func(b []byte) (n int, err error) {
+					require.Len(t, b, 15)
+					return 15, nil
+				}
+
+				return func() {
+					randRead = rand.Read
+				}
+			},
+			want: &drawioxml.MxFile{Diagram: drawioxml.Diagram{MxGraphModel: drawioxml.MxGraphModel{
+				Root: drawioxml.Root{MxCells: []drawioxml.MxCell{
+					{ID: "0"},
+					{ID: "1", Parent: "0"},
+					{
+						ID: "aaaaaaaaaaaaaaa-10", Value: "vpc", Style: "shape=mxgraph.aws4.group;container=1;",
+						Parent: "1", Vertex: "1",
+						Geometry: &drawioxml.Geometry{
+							X: "-20", Y: "-40", Width: 80, Height: 236, As: "geometry",
+						},
+					},
+					{
+						ID: "aaaaaaaaaaaaaaa-1", Value: "lambda1", Parent: "aaaaaaaaaaaaaaa-10", Vertex: "1",
+						Geometry: &drawioxml.Geometry{
+							X: "20", Y: "40", Width: 40, Height: 40, As: "geometry",
+						},
+					},
+					{
+						ID: "aaaaaaaaaaaaaaa-2", Value: "lambda2", Parent: "aaaaaaaaaaaaaaa-10", Vertex: "1",
+						Geometry: &drawioxml.Geometry{
+							X: "20", Y: "176", Width: 40, Height: 40, As: "geometry",
+						},
+					},
+					{
+						ID: "aaaaaaaaaaaaaaa-4", Style: connectedVertically, Parent: "1", Edge: "1",
 						Source: "aaaaaaaaaaaaaaa-1", Target: "aaaaaaaaaaaaaaa-2",
 						Geometry: &drawioxml.Geometry{As: "geometry"},
 					},
@@ -99,7 +268,6 @@ func TestTransformer_Transform(t *testing.T) {
 						{Source: lambda1, Target: lambda2},
 					},
 				},
-				g: graphviz.New(),
 			},
 			setup: func() (tearDown func()) {
 				randRead = func(b []byte) (n int, err error) {
@@ -118,23 +286,23 @@ func TestTransformer_Transform(t *testing.T) {
 					{
 						ID: "-1", Value: "lambda1", Parent: "1", Vertex: "1",
 						Geometry: &drawioxml.Geometry{
-							X: "96.8597", Y: "-85.8", Width: 40, Height: 40, As: "geometry",
+							X: "0", Y: "0", Width: 40, Height: 40, As: "geometry",
 						},
 					},
 					{
 						ID: "-2", Value: "lambda2", Parent: "1", Vertex: "1",
 						Geometry: &drawioxml.Geometry{
-							X: "96.8597", Y: "-13.8", Width: 40, Height: 40, As: "geometry",
+							X: "0", Y: "76", Width: 40, Height: 40, As: "geometry",
 						},
 					},
 					{
 						ID: "-3", Value: "lambda3", Parent: "1", Vertex: "1",
 						Geometry: &drawioxml.Geometry{
-							X: "308.8597", Y: "-85.8", Width: 40, Height: 40, As: "geometry",
+							X: "58", Y: "0", Width: 40, Height: 40, As: "geometry",
 						},
 					},
 					{
-						ID: "-4", Parent: "1", Edge: "1",
+						ID: "-4", Style: connectedVertically, Parent: "1", Edge: "1",
 						Source: "-1", Target: "-2",
 						Geometry: &drawioxml.Geometry{As: "geometry"},
 					},
@@ -150,9 +318,54 @@ func TestTransformer_Transform(t *testing.T) {
 
 			tr := NewTransformer(tt.fields.resCollection, tt.fields.config)
 
-			got := tr.Transform()
+			got, err := tr.Transform()
 
+			require.NoError(t, err)
 			require.Equal(t, tt.want, got)
 		})
 	}
 }
+
+func TestNewDiffTransformer(t *testing.T) {
+	randRead = func(b []byte) (n int, err error) { return len(b), nil }
+	defer func() { randRead = rand.Read }()
+
+	newLayouter = func(config *layout.Config) layout.Layouter { return layout.NewLayered(config) }
+	defer func() { newLayouter = layout.New }()
+
+	lambda := resources.NewGenericResource("1", "lambda1", "lambda")
+	queue := resources.NewGenericResource("2", "queue", "sqs")
+	stream := resources.NewGenericResource("3", "stream", "kinesis")
+	renamedQueue := resources.NewGenericResource("2", "orders-queue", "sqs")
+
+	before := &resources.ResourceCollection{
+		Resources:     []resources.Resource{lambda, queue},
+		Relationships: []resources.Relationship{{Source: lambda, Target: queue}},
+	}
+
+	after := &resources.ResourceCollection{
+		Resources:     []resources.Resource{lambda, renamedQueue, stream},
+		Relationships: []resources.Relationship{{Source: lambda, Target: stream, Label: "writes to"}},
+	}
+
+	config := &Config{NodeStyles: map[string]string{"sqs": "shape=sqs;"}}
+
+	got, err := NewDiffTransformer(before, after, config).Transform()
+	require.NoError(t, err)
+
+	styles := map[string]string{}
+	for _, cell := range got.Diagram.MxGraphModel.Root.MxCells {
+		styles[cell.Source+"->"+cell.Target+cell.Value] = cell.Style
+	}
+
+	require.Equal(t, map[string]string{
+		"->":             "",
+		"->lambda1":      "",
+		"->orders-queue": "shape=sqs;" + DiffStyles[resources.DiffModified],
+		"->stream":       DiffStyles[resources.DiffAdded],
+		"aaaaaaaaaaaaaaa-1->aaaaaaaaaaaaaaa-3writes to": "exitX=0.5;exitY=1;entryX=0.5;entryY=0;" +
+			DiffStyles[resources.DiffAdded],
+		"aaaaaaaaaaaaaaa-1->aaaaaaaaaaaaaaa-2": "exitX=0.5;exitY=1;entryX=0.5;entryY=0;" +
+			DiffStyles[resources.DiffRemoved],
+	}, styles)
+}
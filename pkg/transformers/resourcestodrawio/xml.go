@@ -0,0 +1,81 @@
+package resourcestodrawio
+
+import (
+	"encoding/xml"
+
+	pdrawioxml "github.com/joselitofilho/drawio-parser-go/pkg/parser/xml"
+
+	"github.com/diagram-code-generator/resources/pkg/layout"
+)
+
+// xmlFile is the draw.io file written by TransformXML. Its cells extend the ones of the parser package with the
+// waypoints of the edges, which the parser geometry can't hold.
+type xmlFile struct {
+	XMLName xml.Name   `xml:"mxfile"`
+	Diagram xmlDiagram `xml:"diagram"`
+}
+
+type xmlDiagram struct {
+	XMLName      xml.Name      `xml:"diagram"`
+	MxGraphModel xmlGraphModel `xml:"mxGraphModel"`
+}
+
+type xmlGraphModel struct {
+	XMLName xml.Name `xml:"mxGraphModel"`
+	Root    xmlRoot  `xml:"root"`
+}
+
+type xmlRoot struct {
+	XMLName xml.Name  `xml:"root"`
+	MxCells []xmlCell `xml:"mxCell"`
+}
+
+type xmlCell struct {
+	pdrawioxml.MxCell
+	Geometry *xmlGeometry `xml:"mxGeometry,omitempty"`
+}
+
+type xmlGeometry struct {
+	pdrawioxml.Geometry
+	Points *xmlPoints `xml:"Array,omitempty"`
+}
+
+// xmlPoints is the list of waypoints of an edge, written as <Array as="points">.
+type xmlPoints struct {
+	As     string     `xml:"as,attr"`
+	Points []xmlPoint `xml:"mxPoint"`
+}
+
+type xmlPoint struct {
+	X string `xml:"x,attr"`
+	Y string `xml:"y,attr"`
+}
+
+// newXMLFile returns the draw.io file of the cells, with the waypoints of the edges by cell ID.
+func newXMLFile(mxFile *pdrawioxml.MxFile, waypoints map[string][]layout.Point) *xmlFile {
+	cells := mxFile.Diagram.MxGraphModel.Root.MxCells
+
+	file := &xmlFile{}
+	file.Diagram.MxGraphModel.Root.MxCells = make([]xmlCell, 0, len(cells))
+
+	for i := range cells {
+		cell := xmlCell{MxCell: cells[i]}
+
+		if cells[i].Geometry != nil {
+			cell.Geometry = &xmlGeometry{Geometry: *cells[i].Geometry}
+
+			if points := waypoints[cells[i].ID]; len(points) > 0 {
+				cell.Geometry.Points = &xmlPoints{As: "points"}
+
+				for _, p := range points {
+					cell.Geometry.Points.Points = append(cell.Geometry.Points.Points,
+						xmlPoint{X: formatCoordinate(p.X), Y: formatCoordinate(p.Y)})
+				}
+			}
+		}
+
+		file.Diagram.MxGraphModel.Root.MxCells = append(file.Diagram.MxGraphModel.Root.MxCells, cell)
+	}
+
+	return file
+}